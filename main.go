@@ -4,6 +4,10 @@ import (
 	"flag"
 	"log"
 	"net/http"
+
+	"github.com/jhyehuang/wasm-example/pkg/metrics"
+	// registers the vm pool and invoke metrics served on /metrics
+	_ "github.com/jhyehuang/wasm-example/src/wavm"
 )
 
 var (
@@ -13,7 +17,10 @@ var (
 
 func main() {
 	flag.Parse()
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	mux.Handle("/", http.FileServer(http.Dir(*dir)))
 	log.Printf("listening on %q...", *listen)
-	err := http.ListenAndServe(*listen, http.FileServer(http.Dir(*dir)))
+	err := http.ListenAndServe(*listen, mux)
 	log.Fatalln(err)
 }
@@ -0,0 +1,307 @@
+// Package metrics is a small subset of the Prometheus client: counter, gauge and
+// histogram vectors registered in a Registry and served in the text exposition format
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefBuckets the default histogram buckets, in seconds
+var DefBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// labelSeparator never appears in valid label values, used to join them into a map key
+const labelSeparator = "\xff"
+
+// series one time series of a vector, identified by its label values
+type series struct {
+	labelValues []string
+	value       float64
+	// histogram only
+	bucketCounts []uint64
+	count        uint64
+}
+
+// vec holds the series of one metric, guarded by lock
+type vec struct {
+	lock       sync.Mutex
+	name       string
+	help       string
+	metricType string
+	labelNames []string
+	buckets    []float64
+	series     map[string]*series
+}
+
+func newVec(name, help, metricType string, buckets []float64, labelNames []string) *vec {
+	return &vec{
+		name:       name,
+		help:       help,
+		metricType: metricType,
+		labelNames: labelNames,
+		buckets:    buckets,
+		series:     make(map[string]*series),
+	}
+}
+
+// update the series of labelValues under lock, create it if not exist
+func (v *vec) update(labelValues []string, fn func(s *series)) {
+	if len(labelValues) != len(v.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values, got %d", v.name, len(v.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, labelSeparator)
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	s, exists := v.series[key]
+	if !exists {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		if v.buckets != nil {
+			s.bucketCounts = make([]uint64, len(v.buckets))
+		}
+		v.series[key] = s
+	}
+	fn(s)
+}
+
+// read the series of labelValues under lock, fn is not called if it does not exist
+func (v *vec) read(labelValues []string, fn func(s *series)) {
+	key := strings.Join(labelValues, labelSeparator)
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	if s, exists := v.series[key]; exists {
+		fn(s)
+	}
+}
+
+// remove the series of labelValues, return false if it does not exist
+func (v *vec) remove(labelValues []string) bool {
+	key := strings.Join(labelValues, labelSeparator)
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	_, exists := v.series[key]
+	delete(v.series, key)
+	return exists
+}
+
+// DeletePartialMatch delete every series whose label values include labels,
+// e.g. all the series of a contract closed, return how many were deleted
+func (v *vec) DeletePartialMatch(labels map[string]string) int {
+	indexes := make(map[int]string, len(labels))
+	for i, name := range v.labelNames {
+		if value, ok := labels[name]; ok {
+			indexes[i] = value
+		}
+	}
+	if len(indexes) != len(labels) {
+		return 0
+	}
+
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	deleted := 0
+	for key, s := range v.series {
+		matches := true
+		for i, value := range indexes {
+			if s.labelValues[i] != value {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			delete(v.series, key)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// write the metric in the text exposition format, series sorted by label values
+func (v *vec) write(sb *strings.Builder) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	fmt.Fprintf(sb, "# HELP %s %s\n", v.name, escapeHelp(v.help))
+	fmt.Fprintf(sb, "# TYPE %s %s\n", v.name, v.metricType)
+
+	keys := make([]string, 0, len(v.series))
+	for k := range v.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := v.series[k]
+		if v.buckets == nil {
+			fmt.Fprintf(sb, "%s%s %s\n", v.name, v.labels(s.labelValues, "", 0), formatFloat(s.value))
+			continue
+		}
+		for i, upper := range v.buckets {
+			fmt.Fprintf(sb, "%s_bucket%s %d\n", v.name, v.labels(s.labelValues, "le", upper), s.bucketCounts[i])
+		}
+		fmt.Fprintf(sb, "%s_bucket%s %d\n", v.name, v.labels(s.labelValues, "le", math.Inf(1)), s.count)
+		fmt.Fprintf(sb, "%s_sum%s %s\n", v.name, v.labels(s.labelValues, "", 0), formatFloat(s.value))
+		fmt.Fprintf(sb, "%s_count%s %d\n", v.name, v.labels(s.labelValues, "", 0), s.count)
+	}
+}
+
+// labels format {name="value",...}, with an extra le label for histogram buckets
+func (v *vec) labels(labelValues []string, le string, upper float64) string {
+	pairs := make([]string, 0, len(labelValues)+1)
+	for i, name := range v.labelNames {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, name, escapeLabelValue(labelValues[i])))
+	}
+	if le != "" {
+		pairs = append(pairs, fmt.Sprintf(`%s="%s"`, le, formatFloat(upper)))
+	}
+	if len(pairs) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "NaN"
+	}
+	return fmt.Sprint(f)
+}
+
+func escapeHelp(help string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(help)
+}
+
+// escapeLabelValue escape a label value as the text exposition format does, only
+// backslash, double quote and line feed
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// CounterVec a counter partitioned by labels, only goes up
+type CounterVec struct {
+	*vec
+}
+
+// NewCounterVec create a counter vector
+func NewCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{newVec(name, help, "counter", nil, labelNames)}
+}
+
+// Inc add 1 to the series of labelValues
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add delta to the series of labelValues, panic if delta is negative
+func (c *CounterVec) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s can not decrease", c.name))
+	}
+	c.update(labelValues, func(s *series) {
+		s.value += delta
+	})
+}
+
+// Value return the current value of the series of labelValues
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	var value float64
+	c.read(labelValues, func(s *series) {
+		value = s.value
+	})
+	return value
+}
+
+// Delete the series of labelValues, e.g. when the contract it belongs to is closed
+func (c *CounterVec) Delete(labelValues ...string) bool {
+	return c.remove(labelValues)
+}
+
+// GaugeVec a gauge partitioned by labels, goes up and down
+type GaugeVec struct {
+	*vec
+}
+
+// NewGaugeVec create a gauge vector
+func NewGaugeVec(name, help string, labelNames ...string) *GaugeVec {
+	return &GaugeVec{newVec(name, help, "gauge", nil, labelNames)}
+}
+
+// Set the series of labelValues to value
+func (g *GaugeVec) Set(value float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) {
+		s.value = value
+	})
+}
+
+// Add delta to the series of labelValues, delta may be negative
+func (g *GaugeVec) Add(delta float64, labelValues ...string) {
+	g.update(labelValues, func(s *series) {
+		s.value += delta
+	})
+}
+
+// Value return the current value of the series of labelValues
+func (g *GaugeVec) Value(labelValues ...string) float64 {
+	var value float64
+	g.read(labelValues, func(s *series) {
+		value = s.value
+	})
+	return value
+}
+
+// Delete the series of labelValues, e.g. when the contract it belongs to is closed
+func (g *GaugeVec) Delete(labelValues ...string) bool {
+	return g.remove(labelValues)
+}
+
+// HistogramVec a histogram partitioned by labels
+type HistogramVec struct {
+	*vec
+}
+
+// NewHistogramVec create a histogram vector, buckets are the sorted upper bounds,
+// nil uses DefBuckets
+func NewHistogramVec(name, help string, buckets []float64, labelNames ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	if !sort.Float64sAreSorted(buckets) {
+		panic(fmt.Sprintf("histogram %s buckets are not sorted", name))
+	}
+	return &HistogramVec{newVec(name, help, "histogram", buckets, labelNames)}
+}
+
+// Observe add a sample to the series of labelValues
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.update(labelValues, func(s *series) {
+		for i, upper := range h.buckets {
+			if value <= upper {
+				s.bucketCounts[i]++
+			}
+		}
+		s.count++
+		s.value += value
+	})
+}
+
+// Count return how many samples the series of labelValues has
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	var count uint64
+	h.read(labelValues, func(s *series) {
+		count = s.count
+	})
+	return count
+}
+
+// Delete the series of labelValues, e.g. when the contract it belongs to is closed
+func (h *HistogramVec) Delete(labelValues ...string) bool {
+	return h.remove(labelValues)
+}
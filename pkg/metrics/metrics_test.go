@@ -0,0 +1,79 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCounterAndGauge(t *testing.T) {
+	counter := NewCounterVec("test_total", "test counter", "contract")
+	counter.Inc("a")
+	counter.Add(2, "a")
+	assert.Equal(t, float64(3), counter.Value("a"))
+	assert.Equal(t, float64(0), counter.Value("b"))
+	assert.Panics(t, func() { counter.Add(-1, "a") })
+	assert.Panics(t, func() { counter.Inc() })
+
+	gauge := NewGaugeVec("test_size", "test gauge", "contract")
+	gauge.Set(5, "a")
+	gauge.Add(-2, "a")
+	assert.Equal(t, float64(3), gauge.Value("a"))
+	assert.True(t, gauge.Delete("a"))
+	assert.False(t, gauge.Delete("a"))
+}
+
+func TestDeletePartialMatch(t *testing.T) {
+	counter := NewCounterVec("test_total", "test counter", "contract", "method")
+	counter.Inc("a", "m1")
+	counter.Inc("a", "m2")
+	counter.Inc("b", "m1")
+	assert.Equal(t, 2, counter.DeletePartialMatch(map[string]string{"contract": "a"}))
+	assert.Equal(t, float64(0), counter.Value("a", "m1"))
+	assert.Equal(t, float64(1), counter.Value("b", "m1"))
+	assert.Equal(t, 0, counter.DeletePartialMatch(map[string]string{"code": "0"}))
+}
+
+func TestLabelValueEscaping(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounterVec("test_total", "test counter", "contract")
+	registry.MustRegister(counter)
+	counter.Inc("a\"b\nc\\d\té")
+
+	assert.Contains(t, registry.Gather(), `test_total{contract="a\"b\nc\\d`+"\té"+`"} 1`)
+}
+
+func TestHistogram(t *testing.T) {
+	histogram := NewHistogramVec("test_seconds", "test histogram", []float64{1, 2}, "method")
+	histogram.Observe(0.5, "m")
+	histogram.Observe(1.5, "m")
+	histogram.Observe(3, "m")
+	assert.Equal(t, uint64(3), histogram.Count("m"))
+
+	registry := NewRegistry()
+	registry.MustRegister(histogram)
+	assert.Error(t, registry.Register(histogram))
+
+	expected := `# HELP test_seconds test histogram
+# TYPE test_seconds histogram
+test_seconds_bucket{method="m",le="1"} 1
+test_seconds_bucket{method="m",le="2"} 2
+test_seconds_bucket{method="m",le="+Inf"} 3
+test_seconds_sum{method="m"} 5
+test_seconds_count{method="m"} 3
+`
+	assert.Equal(t, expected, registry.Gather())
+}
+
+func TestServeHTTP(t *testing.T) {
+	registry := NewRegistry()
+	counter := NewCounterVec("test_total", "test counter")
+	registry.MustRegister(counter)
+	counter.Inc()
+
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, contentType, recorder.Header().Get("Content-Type"))
+	assert.Contains(t, recorder.Body.String(), "test_total 1\n")
+}
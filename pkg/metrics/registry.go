@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// contentType of the text exposition format
+const contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// Collector is implemented by CounterVec, GaugeVec and HistogramVec
+type Collector interface {
+	write(sb *strings.Builder)
+	metricName() string
+}
+
+func (v *vec) metricName() string {
+	return v.name
+}
+
+// Registry the set of metrics served by one endpoint
+type Registry struct {
+	lock       sync.RWMutex
+	collectors map[string]Collector
+}
+
+// DefaultRegistry the registry served by Handler
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry create an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		collectors: make(map[string]Collector),
+	}
+}
+
+// Register the collector, fail if one with the same name is registered
+func (r *Registry) Register(c Collector) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, exists := r.collectors[c.metricName()]; exists {
+		return fmt.Errorf("metric %s is already registered", c.metricName())
+	}
+	r.collectors[c.metricName()] = c
+	return nil
+}
+
+// MustRegister register the collectors, panic on error
+func (r *Registry) MustRegister(cs ...Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Gather return all metrics in the text exposition format, sorted by name
+func (r *Registry) Gather() string {
+	r.lock.RLock()
+	names := make([]string, 0, len(r.collectors))
+	for name := range r.collectors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	collectors := make([]Collector, 0, len(names))
+	for _, name := range names {
+		collectors = append(collectors, r.collectors[name])
+	}
+	r.lock.RUnlock()
+
+	var sb strings.Builder
+	for _, c := range collectors {
+		c.write(&sb)
+	}
+	return sb.String()
+}
+
+// ServeHTTP serve the metrics of the registry
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write([]byte(r.Gather()))
+}
+
+// MustRegister register the collectors in DefaultRegistry, panic on error
+func MustRegister(cs ...Collector) {
+	DefaultRegistry.MustRegister(cs...)
+}
+
+// Handler serve the metrics of DefaultRegistry, mount it on /metrics
+func Handler() http.Handler {
+	return DefaultRegistry
+}
@@ -4,6 +4,9 @@ package wasmer
 import "C"
 import (
 	"chainmaker.org/chainmaker/protocol/v2"
+	"fmt"
+	"math"
+	"strings"
 )
 
 // CompilerKind represents the possible compiler types.
@@ -87,23 +90,123 @@ func IsEngineAvailable(engine EngineKind) bool {
 }
 
 // Config holds the compiler and the Engine used by the Store.
+//
+// Config only records the configuration; the C configuration is built
+// from it every time an Engine is created, see NewEngineWithConfig.
 type Config struct {
-	_inner *C.wasm_config_t
+	// The engine and the compiler, the defaults of Wasmer if nil.
+	engine   *EngineKind
+	compiler *CompilerKind
+	target   *Target
+	features *Features
+	// Set by CanonicalizeNaNs.
+	canonicalizeNaNs bool
+	// Set by PushMeteringMiddleware.
+	metering *Metering
 }
 
 // NewConfig instantiates and returns a new Config.
 //
 //   config := NewConfig()
 func NewConfig() *Config {
-	config := C.wasm_config_new(protocol.GasLimit)
+	return &Config{}
+}
+
+// inner builds the C configuration; its ownership goes to the Engine
+// created from it.
+//
+// The C API of this fork always installs a metering middleware, whose
+// initial limit is protocol.GasLimit. When a Metering is pushed, that
+// middleware gets an unbounded limit, so that it never traps: the
+// points of the Metering, whose middleware comes last and owns the
+// exported metering globals, are then the only ones to run out.
+func (self *Config) inner() *C.wasm_config_t {
+	gasLimit := uint64(protocol.GasLimit)
+
+	if self.metering != nil {
+		gasLimit = math.MaxUint64
+	}
+
+	config := C.wasm_config_new(C.uint64_t(gasLimit))
+
+	if self.engine != nil {
+		C.wasm_config_set_engine(config, uint32(C.wasmer_engine_t(*self.engine)))
+	}
+
+	if self.compiler != nil {
+		C.wasm_config_set_compiler(config, uint32(C.wasmer_compiler_t(*self.compiler)))
+	}
+
+	if self.target != nil {
+		C.wasm_config_set_target(config, self.target.inner())
+	}
 
-	return &Config{
-		_inner: config,
+	if self.features != nil {
+		C.wasm_config_set_features(config, self.features.inner())
 	}
+
+	if self.canonicalizeNaNs {
+		C.wasm_config_canonicalize_nans(config, C.bool(true))
+	}
+
+	return config
 }
 
-func (self *Config) inner() *C.wasm_config_t {
-	return self._inner
+// Profile describes everything the Config sets that changes the code
+// it compiles: the engine, the compiler, the target, the NaN
+// canonicalization, the features and the metering, i.e. the cost model
+// and the initial limit of the points. Modules compiled under
+// different profiles must not be mixed up, e.g. by a cache of
+// serialized modules.
+//
+//   NewConfig().Profile() // "engine=default/compiler=default/target=host/nans=native/features=default/metering=..."
+func (self *Config) Profile() string {
+	engine := "default"
+
+	if self.engine != nil {
+		engine = self.engine.String()
+	}
+
+	compiler := "default"
+
+	if self.compiler != nil {
+		compiler = self.compiler.String()
+	}
+
+	target := "host"
+
+	if self.target != nil {
+		target = self.target.description
+	}
+
+	nans := "native"
+
+	if self.canonicalizeNaNs {
+		nans = "canonical"
+	}
+
+	features := "default"
+
+	if self.features != nil {
+		features = self.features.String()
+	}
+
+	// The metering of the C API, then the one of the Metering, see inner.
+	metering := fmt.Sprintf("%d", uint64(protocol.GasLimit))
+
+	if self.metering != nil {
+		model := newMeteringModel(self.metering)
+		metering = fmt.Sprintf("%d,%d:%s", uint64(math.MaxUint64), model.initialLimit, model.digest)
+	}
+
+	return strings.Join([]string{
+		"engine=" + engine,
+		"compiler=" + compiler,
+		"target=" + target,
+		"nans=" + nans,
+		"features=" + features,
+		"metering=" + metering,
+	}, "/")
 }
 
 // UseNativeEngine sets the engine to Universal in the configuration.
@@ -118,7 +221,8 @@ func (self *Config) UseUniversalEngine() *Config {
 		panic("This `wasmer-go` version doesn't include the Universal engine; use `IsEngineAvailable(UNIVERSAL)` to avoid this panic")
 	}
 
-	C.wasm_config_set_engine(self.inner(), uint32(C.wasmer_engine_t(UNIVERSAL)))
+	engine := UNIVERSAL
+	self.engine = &engine
 
 	return self
 }
@@ -135,7 +239,8 @@ func (self *Config) UseDylibEngine() *Config {
 		panic("This `wasmer-go` version doesn't include the DYLIB engine; use `IsEngineAvailable(DYLIB)` to avoid this panic")
 	}
 
-	C.wasm_config_set_engine(self.inner(), uint32(C.wasmer_engine_t(DYLIB)))
+	engine := DYLIB
+	self.engine = &engine
 
 	return self
 }
@@ -164,7 +269,8 @@ func (self *Config) UseCraneliftCompiler() *Config {
 		panic("This `wasmer-go` version doesn't include the Cranelift compiler; use `IsCompilerAvailable(CRANELIFT)` to avoid this panic")
 	}
 
-	C.wasm_config_set_compiler(self.inner(), uint32(C.wasmer_compiler_t(CRANELIFT)))
+	compiler := CRANELIFT
+	self.compiler = &compiler
 
 	return self
 }
@@ -181,7 +287,8 @@ func (self *Config) UseLLVMCompiler() *Config {
 		panic("This `wasmer-go` version doesn't include the LLVM compiler; use `IsCompilerAvailable(LLVM)` to avoid this panic")
 	}
 
-	C.wasm_config_set_compiler(self.inner(), uint32(C.wasmer_compiler_t(LLVM)))
+	compiler := LLVM
+	self.compiler = &compiler
 
 	return self
 }
@@ -199,7 +306,21 @@ func (self *Config) UseSinglepassCompiler() *Config {
 		panic("This `wasmer-go` version doesn't include the Singlepass compiler; use `IsCompilerAvailable(SINGLEPASS)` to avoid this panic")
 	}
 
-	C.wasm_config_set_compiler(self.inner(), uint32(C.wasmer_compiler_t(SINGLEPASS)))
+	compiler := SINGLEPASS
+	self.compiler = &compiler
+
+	return self
+}
+
+// CanonicalizeNaNs enables or disables the canonicalization of NaNs
+// in the configuration. When enabled, every float operation that
+// may produce a NaN produces the canonical one, so the results are
+// bit-identical on every host.
+//
+//   config := NewConfig()
+//   config.CanonicalizeNaNs(true)
+func (self *Config) CanonicalizeNaNs(enable bool) *Config {
+	self.canonicalizeNaNs = enable
 
 	return self
 }
@@ -213,7 +334,7 @@ func (self *Config) UseSinglepassCompiler() *Config {
 //   config := NewConfig()
 //   config.UseTarget(target)
 func (self *Config) UseTarget(target *Target) *Config {
-	C.wasm_config_set_target(self.inner(), target.inner())
+	self.target = target
 
 	return self
 }
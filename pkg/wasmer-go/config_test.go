@@ -25,7 +25,8 @@ func TestEngineKind(t *testing.T) {
 func TestConfig(t *testing.T) {
 	config := NewConfig()
 
-	engine := NewEngineWithConfig(config)
+	engine, err := NewEngineWithConfig(config)
+	assert.NoError(t, err)
 	store := NewStore(engine)
 	module, err := NewModule(store, testGetBytes("tests.wasm"), nil)
 	assert.NoError(t, err)
@@ -41,6 +42,32 @@ func TestConfig(t *testing.T) {
 	assert.Equal(t, result, int32(42))
 }
 
+func TestConfigCanonicalizeNaNs(t *testing.T) {
+	config := NewConfig().CanonicalizeNaNs(true)
+
+	engine, err := NewEngineWithConfig(config)
+	assert.NoError(t, err)
+	store := NewStore(engine)
+	module, err := NewModule(store, []byte(`
+		(module
+		  (func (export "nan") (result i32)
+		    f32.const 0
+		    f32.const 0
+		    f32.div
+		    i32.reinterpret_f32))`), nil)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, NewImportObject())
+	assert.NoError(t, err)
+
+	nan, err := instance.Exports.GetFunction("nan")
+	assert.NoError(t, err)
+
+	result, err := nan()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0x7fc00000), result)
+}
+
 func TestConfig_AllCombinations(t *testing.T) {
 	type Test struct {
 		compilerName string
@@ -92,7 +119,8 @@ func TestConfig_AllCombinations(t *testing.T) {
 		t.Run(
 			fmt.Sprintf("compiler=%s, engine=%s", test.compilerName, test.engineName),
 			func(t *testing.T) {
-				engine := NewEngineWithConfig(test.config)
+				engine, err := NewEngineWithConfig(test.config)
+				assert.NoError(t, err)
 				store := NewStore(engine)
 				module, err := NewModule(store, testGetBytes("tests.wasm"), nil)
 				assert.NoError(t, err)
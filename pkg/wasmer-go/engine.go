@@ -10,6 +10,10 @@ import (
 // execution of a WebAssembly module.
 type Engine struct {
 	_inner *C.wasm_engine_t
+	// The cost model of the modules this Engine compiles, if any.
+	metering *meteringModel
+	// The profile of the Config of the Engine, see Profile.
+	profile string
 }
 
 func newEngine(engine *C.wasm_engine_t) *Engine {
@@ -19,6 +23,10 @@ func newEngine(engine *C.wasm_engine_t) *Engine {
 
 	runtime.SetFinalizer(self, func(self *Engine) {
 		C.wasm_engine_delete(self.inner())
+
+		if self.metering != nil {
+			releaseMeteringModel(self.metering)
+		}
 	})
 
 	return self
@@ -35,10 +43,52 @@ func NewEngine() *Engine {
 // NewEngineWithConfig instantiates and returns a new Engine with the given configuration.
 //
 //   config := NewConfig()
-//   engine := NewEngineWithConfig(config)
+//   engine, err := NewEngineWithConfig(config)
+//
+// It returns an error if the Metering of the configuration can't be
+// used, because 64 other cost models already are, see
+// Config.PushMeteringMiddleware.
+func NewEngineWithConfig(config *Config) (*Engine, error) {
+	var metering *meteringModel
+
+	if config.metering != nil {
+		var err error
+
+		if metering, err = acquireMeteringModel(config.metering); err != nil {
+			return nil, err
+		}
+	}
+
+	return newEngineWithConfig(config, metering), nil
+}
+
+// newEngineWithConfig creates an Engine from the configuration and
+// the model of its Metering, if any.
+func newEngineWithConfig(config *Config, metering *meteringModel) *Engine {
+	inner := config.inner()
+
+	if metering != nil {
+		pushMeteringMiddleware(inner, metering)
+	}
+
+	self := newEngine(C.wasm_engine_new_with_config(inner))
+	self.metering = metering
+	self.profile = config.Profile()
+
+	return self
+}
+
+// Profile describes the configuration of the Engine, see
+// Config.Profile; it is "default" for an Engine created by NewEngine.
 //
-func NewEngineWithConfig(config *Config) *Engine {
-	return newEngine(C.wasm_engine_new_with_config(config.inner()))
+//   engine := NewDeterministicEngine()
+//   engine.Profile() == NewDeterministicConfig().Profile() // true
+func (self *Engine) Profile() string {
+	if self.profile == "" {
+		return "default"
+	}
+
+	return self.profile
 }
 
 // NewUniversalEngine instantiates and returns a new Universal engine.
@@ -49,7 +99,46 @@ func NewUniversalEngine() *Engine {
 	config := NewConfig()
 	config.UseUniversalEngine()
 
-	return NewEngineWithConfig(config)
+	return newEngineWithConfig(config, nil)
+}
+
+// NewDeterministicConfig instantiates and returns the Config of
+// NewDeterministicEngine: the Universal engine, the Singlepass
+// compiler, canonical NaNs and the blockchain-safe features (see
+// NewBlockchainSafeFeatures), on top of the metering every Config
+// comes with (see NewConfig).
+//
+//   config := NewDeterministicConfig()
+//
+// This function might fail if the Singlepass compiler isn't
+// available. Check `IsCompilerAvailable` to learn more.
+func NewDeterministicConfig() *Config {
+	return NewConfig().
+		UseUniversalEngine().
+		UseSinglepassCompiler().
+		CanonicalizeNaNs(true).
+		UseFeatures(NewBlockchainSafeFeatures())
+}
+
+// NewDeterministicEngine instantiates and returns a new Universal
+// engine whose execution is identical on every host, as consensus
+// requires, see NewDeterministicConfig.
+//
+//   engine := NewDeterministicEngine()
+//
+// This function might fail if the Singlepass compiler isn't
+// available. Check `IsCompilerAvailable` to learn more.
+func NewDeterministicEngine() *Engine {
+	return newEngineWithConfig(NewDeterministicConfig(), nil)
+}
+
+// DeterministicEngineProfile describes the configuration of
+// NewDeterministicEngine, e.g. to be part of a cache key: artifacts
+// compiled under another profile must not be loaded by it.
+//
+//   DeterministicEngineProfile() == NewDeterministicEngine().Profile() // true
+func DeterministicEngineProfile() string {
+	return NewDeterministicConfig().Profile()
 }
 
 // NewDylibEngine instantiates and returns a new Dylib engine.
@@ -60,7 +149,7 @@ func NewDylibEngine() *Engine {
 	config := NewConfig()
 	config.UseDylibEngine()
 
-	return NewEngineWithConfig(config)
+	return newEngineWithConfig(config, nil)
 }
 
 func (self *Engine) inner() *C.wasm_engine_t {
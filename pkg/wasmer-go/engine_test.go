@@ -26,6 +26,10 @@ func TestEngine(t *testing.T) {
 	testEngine(t, NewEngine())
 }
 
+func TestDeterministicEngine(t *testing.T) {
+	testEngine(t, NewDeterministicEngine())
+}
+
 func TestJITEngine(t *testing.T) {
 	testEngine(t, NewJITEngine())
 }
@@ -50,7 +54,8 @@ func TestEngineWithTarget(t *testing.T) {
 	config := NewConfig()
 	config.UseTarget(target)
 
-	engine := NewEngineWithConfig(config)
+	engine, err := NewEngineWithConfig(config)
+	assert.NoError(t, err)
 	store := NewStore(engine)
 
 	module, err := NewModule(store, testGetBytes("tests.wasm"), nil)
@@ -58,3 +63,23 @@ func TestEngineWithTarget(t *testing.T) {
 
 	_ = module
 }
+
+func TestEngineProfile(t *testing.T) {
+	assert.Equal(t, DeterministicEngineProfile(), NewDeterministicEngine().Profile())
+	assert.Equal(t, "default", NewEngine().Profile())
+
+	profile := NewDeterministicConfig().Profile()
+	assert.Contains(t, profile, "engine=universal/compiler=singlepass/")
+	assert.Contains(t, profile, "nans=canonical/features=bulk_memory,reference_types,multi_value/")
+
+	// whatever changes the compiled code changes the profile
+	assert.NotEqual(t, profile, NewDeterministicConfig().CanonicalizeNaNs(false).Profile())
+	assert.NotEqual(t, profile, NewDeterministicConfig().UseFeatures(NewFeatures()).Profile())
+
+	metering := &Metering{InitialLimit: 1000, DefaultCost: 1}
+	metered := NewDeterministicConfig().PushMeteringMiddleware(metering).Profile()
+	assert.NotEqual(t, profile, metered)
+
+	metering.OpcodeCosts = map[Opcode]uint64{OpMemoryGrow: 100}
+	assert.NotEqual(t, metered, NewDeterministicConfig().PushMeteringMiddleware(metering).Profile())
+}
@@ -3,7 +3,9 @@ package wasmer
 // #include <wasmer.h>
 import "C"
 import (
+	"fmt"
 	"runtime"
+	"strings"
 	"unsafe"
 )
 
@@ -62,16 +64,36 @@ type TrapError struct {
 	message string
 	origin  *Frame
 	trace   []*Frame
+	kind    TrapKind
 }
 
-func newErrorFromTrap(pointer *C.wasm_trap_t) *TrapError {
+// newErrorFromTrap resolves the frames of the trap with the symbols
+// and the exports of the called instance, when known.
+func newErrorFromTrap(pointer *C.wasm_trap_t, symbols *moduleSymbols, exports *Exports) *TrapError {
 	trap := newTrap(pointer, nil)
 
-	return &TrapError{
+	self := &TrapError{
 		message: trap.Message(),
 		origin:  trap.Origin(),
 		trace:   trap.Trace().frames,
 	}
+
+	for _, frame := range append([]*Frame{self.origin}, self.trace...) {
+		if frame != nil {
+			frame.symbols = symbols
+			frame.exports = exports
+		}
+	}
+
+	self.kind = trapKindOf(self.message)
+
+	// The metering middleware traps with `unreachable` once the
+	// points are exhausted.
+	if self.kind == TrapUnreachable && exports != nil && exports.meteringPointsExhausted() {
+		self.kind = TrapOutOfGas
+	}
+
+	return self
 }
 
 // Error returns the TrapError's message.
@@ -88,3 +110,102 @@ func (self *TrapError) Origin() *Frame {
 func (self *TrapError) Trace() []*Frame {
 	return self.trace
 }
+
+// Kind returns the cause of the TrapError.
+func (self *TrapError) Kind() TrapKind {
+	return self.kind
+}
+
+// Backtrace returns the trace as text, one frame per line starting at
+// the frame that trapped. See Frame.String.
+//
+//   fmt.Printf("%s trap: %s\n%s", trapError.Kind(), trapError, trapError.Backtrace())
+func (self *TrapError) Backtrace() string {
+	var builder strings.Builder
+
+	for nth, frame := range self.trace {
+		fmt.Fprintf(&builder, "  #%d %s\n", nth, frame)
+	}
+
+	return builder.String()
+}
+
+// TrapKind is the cause of a TrapError.
+type TrapKind uint8
+
+const (
+	// Any other trap, e.g. an error returned by a host function.
+	TrapOther TrapKind = iota
+	// An `unreachable` instruction.
+	TrapUnreachable
+	// A memory or table access out of bounds, or misaligned.
+	TrapOutOfBounds
+	// An integer division or remainder by zero.
+	TrapDivisionByZero
+	// An integer overflow, e.g. of the minimal integer divided by -1.
+	TrapIntegerOverflow
+	// A float truncated to an integer it doesn't fit in.
+	TrapInvalidConversion
+	// A `call_indirect` to a null element or to a function of
+	// another type.
+	TrapIndirectCall
+	// The call stack is exhausted.
+	TrapStackOverflow
+	// The metering points are exhausted.
+	TrapOutOfGas
+)
+
+// String returns the TrapKind as a string.
+//
+//   TrapDivisionByZero.String() // "div-by-zero"
+func (self TrapKind) String() string {
+	switch self {
+	case TrapOther:
+		return "other"
+	case TrapUnreachable:
+		return "unreachable"
+	case TrapOutOfBounds:
+		return "out-of-bounds"
+	case TrapDivisionByZero:
+		return "div-by-zero"
+	case TrapIntegerOverflow:
+		return "integer-overflow"
+	case TrapInvalidConversion:
+		return "invalid-conversion"
+	case TrapIndirectCall:
+		return "indirect-call"
+	case TrapStackOverflow:
+		return "stack-overflow"
+	case TrapOutOfGas:
+		return "out-of-gas"
+	}
+
+	return fmt.Sprintf("TrapKind(%d)", uint8(self))
+}
+
+// trapKinds are the messages of the traps of Wasmer, by kind.
+var trapKinds = []struct {
+	message string
+	kind    TrapKind
+}{
+	{"call stack exhausted", TrapStackOverflow},
+	{"out of bounds", TrapOutOfBounds},
+	{"misaligned memory access", TrapOutOfBounds},
+	{"unaligned atomic access", TrapOutOfBounds},
+	{"uninitialized element", TrapIndirectCall},
+	{"indirect call type mismatch", TrapIndirectCall},
+	{"integer divide by zero", TrapDivisionByZero},
+	{"integer overflow", TrapIntegerOverflow},
+	{"invalid conversion to integer", TrapInvalidConversion},
+	{"unreachable", TrapUnreachable},
+}
+
+func trapKindOf(message string) TrapKind {
+	for _, trapKind := range trapKinds {
+		if strings.Contains(message, trapKind.message) {
+			return trapKind.kind
+		}
+	}
+
+	return TrapOther
+}
@@ -14,6 +14,8 @@ type Exports struct {
 	_inner   C.wasm_extern_vec_t
 	exports  map[string]*Extern
 	instance *C.wasm_instance_t
+	// The symbols of the module, to resolve the frames of a trap.
+	symbols *moduleSymbols
 }
 
 func newExports(instance *C.wasm_instance_t, module *Module) *Exports {
@@ -41,6 +43,7 @@ func newExports(instance *C.wasm_instance_t, module *Module) *Exports {
 
 	self.exports = exports
 	self.instance = instance
+	self.symbols = module.symbols
 
 	return self
 }
@@ -187,7 +190,12 @@ func (self *Exports) GetWasiStartFunction() (NativeFunction, error) {
 		return nil, newErrorWith("WASI start function was not found")
 	}
 
-	return newFunction(start, nil, nil).Native(), nil
+	// The start function is a copy the Function owns, its traps are
+	// still resolved by the Exports.
+	function := newFunction(start, nil, nil)
+	function.exports = self
+
+	return function.Native(), nil
 }
 
 // Force to close the Exports.
@@ -0,0 +1,141 @@
+package wasmer
+
+// #include <wasmer.h>
+//
+// extern void set_extern_ref_handle(wasm_foreign_t *foreign, uintptr_t handle);
+// extern uintptr_t get_extern_ref_handle(const wasm_ref_t *ref);
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"unsafe"
+)
+
+// ExternRef is an opaque reference to a Go object, passed to and from
+// WebAssembly as an `externref` value (the AnyRef ValueKind).
+//
+// WebAssembly never sees the Go object, only a handle into a Go-side
+// registry. The object stays in the registry as long as the engine or
+// Go holds an ExternRef to it, and a handle is never reused, so a
+// guest can't forge a reference to another object.
+//
+//   cursor := db.NewCursor()
+//   ref := wasmer.NewExternRef(store, cursor)
+//   iterate, _ := instance.Exports.GetFunction("iterate")
+//   _, _ = iterate(ref)
+type ExternRef struct {
+	_inner *C.wasm_ref_t
+}
+
+func newExternRef(pointer *C.wasm_ref_t) *ExternRef {
+	ref := &ExternRef{_inner: pointer}
+
+	runtime.SetFinalizer(ref, func(ref *ExternRef) {
+		C.wasm_ref_delete(ref.inner())
+	})
+
+	return ref
+}
+
+// NewExternRef registers object and returns a new ExternRef to it in
+// the given Store.
+//
+//   ref := wasmer.NewExternRef(store, iterator)
+//   value := wasmer.NewAnyRef(ref)
+func NewExternRef(store *Store, object interface{}) *ExternRef {
+	handle := externRefStore.store(object)
+	foreign := C.wasm_foreign_new(store.inner())
+
+	runtime.KeepAlive(store)
+
+	// The engine calls extern_ref_finalizer once the last reference
+	// is dropped, which unregisters object.
+	C.set_extern_ref_handle(foreign, C.uintptr_t(handle))
+
+	return newExternRef(C.wasm_foreign_as_ref(foreign))
+}
+
+func (self *ExternRef) inner() *C.wasm_ref_t {
+	return self._inner
+}
+
+// Object returns the Go object the ExternRef refers to, or an Error if
+// the reference was not created by NewExternRef.
+//
+//   ref := value.ExternRef()
+//   iterator, _ := ref.Object()
+func (self *ExternRef) Object() (interface{}, error) {
+	handle := uintptr(C.get_extern_ref_handle(self.inner()))
+
+	runtime.KeepAlive(self)
+
+	return externRefStore.load(handle)
+}
+
+// Same returns true if both ExternRefs refer to the same object.
+//
+//   ref := wasmer.NewExternRef(store, iterator)
+//   _ = ref.Same(value.ExternRef()) // true if the guest returned it
+func (self *ExternRef) Same(other *ExternRef) bool {
+	same := C.wasm_ref_same(self.inner(), other.inner())
+
+	runtime.KeepAlive(self)
+	runtime.KeepAlive(other)
+
+	return bool(same)
+}
+
+// copyRef returns an owned reference to the same object, which the
+// caller must delete.
+func (self *ExternRef) copyRef() *C.wasm_ref_t {
+	ref := C.wasm_ref_copy(self.inner())
+
+	runtime.KeepAlive(self)
+
+	return ref
+}
+
+//export extern_ref_finalizer
+func extern_ref_finalizer(handle unsafe.Pointer) {
+	externRefStore.remove(uintptr(handle))
+}
+
+type externRefs struct {
+	sync.RWMutex
+	objects map[uintptr]interface{}
+	// The last handle given out, 0 is never a valid handle.
+	last uintptr
+}
+
+func (self *externRefs) load(handle uintptr) (interface{}, error) {
+	self.RLock()
+	object, exists := self.objects[handle]
+	self.RUnlock()
+
+	if !exists {
+		return nil, newErrorWith(fmt.Sprintf("Extern reference `%d` does not exist", handle))
+	}
+
+	return object, nil
+}
+
+func (self *externRefs) store(object interface{}) uintptr {
+	self.Lock()
+	self.last++
+	handle := self.last
+	self.objects[handle] = object
+	self.Unlock()
+
+	return handle
+}
+
+func (self *externRefs) remove(handle uintptr) {
+	self.Lock()
+	delete(self.objects, handle)
+	self.Unlock()
+}
+
+var externRefStore = externRefs{
+	objects: make(map[uintptr]interface{}),
+}
@@ -0,0 +1,105 @@
+package wasmer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const externRefWat = `(module
+	(func (export "identity") (param externref) (result externref)
+		local.get 0)
+	(func (export "is_null") (param externref) (result i32)
+		local.get 0
+		ref.is_null)
+	(func $answer (result i32) i32.const 42)
+	(elem declare func $answer)
+	(func (export "answer") (result funcref)
+		ref.func $answer))`
+
+func testGetExternRefInstance(t *testing.T) (*Store, *Instance) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	module, err := NewModuleFromWat(store, externRefWat, nil)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, NewImportObject())
+	assert.NoError(t, err)
+
+	return store, instance
+}
+
+type cursor struct {
+	position int
+}
+
+func TestExternRef(t *testing.T) {
+	store, instance := testGetExternRefInstance(t)
+
+	identity, err := instance.Exports.GetFunction("identity")
+	assert.NoError(t, err)
+
+	object := &cursor{position: 7}
+	ref := NewExternRef(store, object)
+
+	result, err := identity(ref)
+	assert.NoError(t, err)
+
+	returned := result.(*ExternRef)
+	assert.True(t, ref.Same(returned))
+
+	objectAgain, err := returned.Object()
+	assert.NoError(t, err)
+	assert.Same(t, object, objectAgain)
+
+	isNull, err := instance.Exports.GetFunction("is_null")
+	assert.NoError(t, err)
+
+	null, err := isNull(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), null)
+
+	null, err = isNull(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), null)
+}
+
+func TestExternRefValue(t *testing.T) {
+	store, _ := testGetExternRefInstance(t)
+
+	ref := NewExternRef(store, "iterator")
+	value := NewAnyRef(ref)
+	assert.Equal(t, AnyRef, value.Kind())
+	assert.True(t, ref.Same(value.ExternRef()))
+
+	object, err := value.ExternRef().Object()
+	assert.NoError(t, err)
+	assert.Equal(t, "iterator", object)
+
+	null := NewAnyRef(nil)
+	assert.Nil(t, null.ExternRef())
+	assert.Panics(t, func() { _ = value.I32() })
+}
+
+func TestFuncRef(t *testing.T) {
+	_, instance := testGetExternRefInstance(t)
+
+	answer, err := instance.Exports.GetFunction("answer")
+	assert.NoError(t, err)
+
+	result, err := answer()
+	assert.NoError(t, err)
+
+	function := result.(*Function)
+	assert.NotNil(t, function)
+
+	value, err := function.Call()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(42), value)
+
+	funcRef := NewFuncRef(function)
+	assert.Equal(t, FuncRef, funcRef.Kind())
+
+	value, err = funcRef.FuncRef().Call()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(42), value)
+}
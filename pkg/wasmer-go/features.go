@@ -0,0 +1,163 @@
+package wasmer
+
+// #include <wasmer.h>
+import "C"
+import (
+	"strings"
+)
+
+// Features holds the WebAssembly proposals a Config accepts. A
+// module using a disabled proposal fails to compile.
+//
+// See https://github.com/WebAssembly/proposals.
+//
+//   features := NewFeatures().SIMD(false).Threads(false)
+//   config := NewConfig().UseFeatures(features)
+type Features struct {
+	simd           bool
+	threads        bool
+	bulkMemory     bool
+	referenceTypes bool
+	multiValue     bool
+	multiMemory    bool
+	memory64       bool
+	tailCall       bool
+	moduleLinking  bool
+}
+
+// NewFeatures instantiates a new Features with the defaults of
+// Wasmer: bulk memory, reference types, multi-value and SIMD are
+// enabled, the other proposals are disabled.
+//
+//   features := NewFeatures()
+func NewFeatures() *Features {
+	return &Features{
+		simd:           true,
+		bulkMemory:     true,
+		referenceTypes: true,
+		multiValue:     true,
+	}
+}
+
+// NewBlockchainSafeFeatures instantiates a new Features suited for
+// smart contracts, whose execution must be identical on every node:
+// threads and SIMD are disabled, as well as every proposal that is
+// not standardized yet; bulk memory, reference types and multi-value
+// stay enabled.
+//
+//   config := NewConfig().UseFeatures(NewBlockchainSafeFeatures())
+func NewBlockchainSafeFeatures() *Features {
+	return NewFeatures().SIMD(false)
+}
+
+// SIMD enables or disables the SIMD proposal.
+func (self *Features) SIMD(enable bool) *Features {
+	self.simd = enable
+	return self
+}
+
+// Threads enables or disables the threads proposal.
+func (self *Features) Threads(enable bool) *Features {
+	self.threads = enable
+	return self
+}
+
+// BulkMemory enables or disables the bulk memory proposal.
+func (self *Features) BulkMemory(enable bool) *Features {
+	self.bulkMemory = enable
+	return self
+}
+
+// ReferenceTypes enables or disables the reference types proposal.
+func (self *Features) ReferenceTypes(enable bool) *Features {
+	self.referenceTypes = enable
+	return self
+}
+
+// MultiValue enables or disables the multi-value proposal.
+func (self *Features) MultiValue(enable bool) *Features {
+	self.multiValue = enable
+	return self
+}
+
+// MultiMemory enables or disables the multi-memory proposal.
+func (self *Features) MultiMemory(enable bool) *Features {
+	self.multiMemory = enable
+	return self
+}
+
+// Memory64 enables or disables the memory64 proposal.
+func (self *Features) Memory64(enable bool) *Features {
+	self.memory64 = enable
+	return self
+}
+
+// TailCall enables or disables the tail call proposal.
+func (self *Features) TailCall(enable bool) *Features {
+	self.tailCall = enable
+	return self
+}
+
+// ModuleLinking enables or disables the module linking proposal.
+func (self *Features) ModuleLinking(enable bool) *Features {
+	self.moduleLinking = enable
+	return self
+}
+
+// String returns the enabled proposals, e.g. to be part of a cache
+// key.
+//
+//   NewBlockchainSafeFeatures().String() // "bulk_memory,reference_types,multi_value"
+func (self *Features) String() string {
+	var enabled []string
+
+	for _, feature := range []struct {
+		name    string
+		enabled bool
+	}{
+		{"simd", self.simd},
+		{"threads", self.threads},
+		{"bulk_memory", self.bulkMemory},
+		{"reference_types", self.referenceTypes},
+		{"multi_value", self.multiValue},
+		{"multi_memory", self.multiMemory},
+		{"memory64", self.memory64},
+		{"tail_call", self.tailCall},
+		{"module_linking", self.moduleLinking},
+	} {
+		if feature.enabled {
+			enabled = append(enabled, feature.name)
+		}
+	}
+
+	return strings.Join(enabled, ",")
+}
+
+// inner creates the C features; its ownership goes to the C
+// configuration they are set on.
+func (self *Features) inner() *C.wasmer_features_t {
+	features := C.wasmer_features_new()
+
+	C.wasmer_features_simd(features, C.bool(self.simd))
+	C.wasmer_features_threads(features, C.bool(self.threads))
+	C.wasmer_features_bulk_memory(features, C.bool(self.bulkMemory))
+	C.wasmer_features_reference_types(features, C.bool(self.referenceTypes))
+	C.wasmer_features_multi_value(features, C.bool(self.multiValue))
+	C.wasmer_features_multi_memory(features, C.bool(self.multiMemory))
+	C.wasmer_features_memory64(features, C.bool(self.memory64))
+	C.wasmer_features_tail_call(features, C.bool(self.tailCall))
+	C.wasmer_features_module_linking(features, C.bool(self.moduleLinking))
+
+	return features
+}
+
+// UseFeatures sets the WebAssembly proposals the configuration
+// accepts.
+//
+//   config := NewConfig()
+//   config.UseFeatures(NewBlockchainSafeFeatures())
+func (self *Config) UseFeatures(features *Features) *Config {
+	self.features = features
+
+	return self
+}
@@ -0,0 +1,30 @@
+package wasmer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFeatures(t *testing.T) {
+	assert.Equal(t, "simd,bulk_memory,reference_types,multi_value", NewFeatures().String())
+	assert.Equal(t, "bulk_memory,reference_types,multi_value", NewBlockchainSafeFeatures().String())
+	assert.Equal(t, "simd,threads", NewFeatures().Threads(true).BulkMemory(false).ReferenceTypes(false).MultiValue(false).String())
+}
+
+func TestConfigUseFeatures(t *testing.T) {
+	simd := []byte(`(module (func (result v128) v128.const i64x2 0 0))`)
+
+	engine, err := NewEngineWithConfig(NewConfig().UseFeatures(NewFeatures()))
+	assert.NoError(t, err)
+	_, err = NewModule(NewStore(engine), simd, nil)
+	assert.NoError(t, err)
+
+	engine, err = NewEngineWithConfig(NewConfig().UseFeatures(NewBlockchainSafeFeatures()))
+	assert.NoError(t, err)
+	store := NewStore(engine)
+	_, err = NewModule(store, simd, nil)
+	assert.Error(t, err)
+
+	_, err = NewModule(store, testGetBytes("tests.wasm"), nil)
+	assert.NoError(t, err)
+}
@@ -36,14 +36,19 @@ type Function struct {
 	funcType 	*FunctionType
 	environment *functionEnvironment
 	lazyNative  NativeFunction
+	// The Exports the function comes from, which resolve the frames
+	// of its traps, if any.
+	exports *Exports
 }
 
 func newFunction(pointer *C.wasm_func_t, environment *functionEnvironment, ownedBy interface{}) *Function {
+	exports, _ := ownedBy.(*Exports)
 	function := &Function{
 		_inner:      pointer,
 		_ownedBy:    ownedBy,
 		environment: environment,
 		lazyNative:  nil,
+		exports:     exports,
 	}
 
 	if ownedBy == nil {
@@ -326,7 +331,11 @@ func (self *Function) Native() NativeFunction {
 		runtime.KeepAlive(results)
 
 		if trap != nil {
-			return nil, newErrorFromTrap(trap)
+			if self.exports == nil {
+				return nil, newErrorFromTrap(trap, nil, nil)
+			}
+
+			return nil, newErrorFromTrap(trap, self.exports.symbols, self.exports)
 		}
 
 		switch results.size {
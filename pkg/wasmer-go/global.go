@@ -106,6 +106,7 @@ func (self *Global) Set(value interface{}, kind ValueKind) error {
 	}
 
 	C.wasm_global_set(self.inner(), &result)
+	C.wasm_val_delete(&result)
 
 	return nil
 }
@@ -119,6 +120,7 @@ func (self *Global) Get() (interface{}, error) {
 	var value C.wasm_val_t
 
 	C.wasm_global_get(self.inner(), &value)
+	defer C.wasm_val_delete(&value)
 
 	return toGoValue(&value), nil
 }
@@ -11,6 +11,9 @@ import (
 // instantiating a WebAssembly module.
 type ImportObject struct {
 	externs map[string]map[string]IntoExtern
+	// Run by NewInstance once the instance is created, e.g. to give
+	// the WASI sandbox the memory of the instance.
+	instantiated []func(*Instance) error
 }
 
 // NewImportObject instantiates a new empty ImportObject.
@@ -3,8 +3,8 @@ package wasmer
 // #include <wasmer.h>
 import "C"
 import (
-	"fmt"
 	"runtime"
+	"sync/atomic"
 )
 
 type Instance struct {
@@ -13,6 +13,13 @@ type Instance struct {
 
 	// without this, imported functions may be freed before execution of an exported function is complete.
 	imports *ImportObject
+
+	// The Instance of a Frame only borrows the instance, Close does
+	// nothing.
+	borrowed bool
+
+	// 1 once Interrupt is called.
+	interrupted int32
 }
 
 // NewInstance instantiates a new Instance.
@@ -55,7 +62,7 @@ func NewInstance(module *Module, imports *ImportObject) (*Instance, error) {
 	}
 
 	if traps != nil {
-		return nil, newErrorFromTrap(traps)
+		return nil, newErrorFromTrap(traps, module.symbols, nil)
 	}
 
 	self := &Instance{
@@ -68,6 +75,14 @@ func NewInstance(module *Module, imports *ImportObject) (*Instance, error) {
 		self.Close()
 	})
 
+	for _, instantiated := range imports.instantiated {
+		if err := instantiated(self); err != nil {
+			self.Close()
+
+			return nil, err
+		}
+	}
+
 	return self, nil
 }
 
@@ -81,34 +96,99 @@ func (self *Instance) inner() *C.wasm_instance_t {
 // possible to force the destruction of the Instance by calling Close
 // manually.
 func (self *Instance) Close() {
+	if self.borrowed {
+		return
+	}
+
 	runtime.SetFinalizer(self, nil)
 	C.wasm_instance_delete(self.inner())
 	self.Exports.Close()
 }
 
-func (self *Instance) GetGasRemaining() uint64 {
-	gasRemainingGlobal, err := self.Exports.GetGlobal("wasmer_metering_remaining_points")
-	if err != nil {
-		panic(fmt.Sprintf("can't find 'wasmer_metering_remaining_points' global variable: %v", err))
+// GetGasRemaining returns the metering points the Instance has left,
+// 0 once they are exhausted.
+//
+// Note: GetGasRemaining returns an Error if the module wasn't compiled
+// with a metering middleware, see Config.PushMeteringMiddleware.
+func (self *Instance) GetGasRemaining() (uint64, error) {
+	if err := self.Exports.metered(); err != nil {
+		return 0, err
 	}
-	//fmt.Sprintf("%v", gasRemainingGlobal)
 
-	gasRemaining, err := gasRemainingGlobal.Get()
-	if err != nil {
-		panic("can't get data from 'wasmer_metering_remaining_points' global variable.")
-	}
+	points := C.wasmer_metering_get_remaining_points(self.inner())
+
+	runtime.KeepAlive(self)
 
-	return uint64(gasRemaining.(int64))
+	return uint64(points), nil
 }
 
-func (self *Instance) SetGasLimit(gas uint64) {
-	gasRemainingGlobal, err := self.Exports.GetGlobal("wasmer_metering_remaining_points")
-	if err != nil {
-		panic(fmt.Sprintf("can't find 'wasmer_metering_remaining_points' global variable: %v", err))
+// SetGasLimit sets the metering points the Instance has left, and
+// resets the exhausted state: an Instance which ran out of points can
+// run again.
+//
+// Note: SetGasLimit returns an Error if the module wasn't compiled
+// with a metering middleware.
+func (self *Instance) SetGasLimit(gas uint64) error {
+	if err := self.Exports.metered(); err != nil {
+		return err
 	}
 
-	if gasRemainingGlobal.Set(int64(gas), I64) != nil {
-		panic("can't get data from 'wasmer_metering_remaining_points' global variable.")
+	C.wasmer_metering_set_remaining_points(self.inner(), C.uint64_t(gas))
+
+	runtime.KeepAlive(self)
+
+	return nil
+}
+
+// PointsExhausted returns true if the Instance trapped because it ran
+// out of metering points since the last SetGasLimit. Unlike a zero
+// GetGasRemaining, it tells a call which used exactly all its points
+// apart from a call which needed more.
+//
+//   _, err := run()
+//
+//   if exhausted, _ := instance.PointsExhausted(); exhausted {
+//       // out of gas
+//   }
+//
+// Note: PointsExhausted returns an Error if the module wasn't
+// compiled with a metering middleware.
+func (self *Instance) PointsExhausted() (bool, error) {
+	if err := self.Exports.metered(); err != nil {
+		return false, err
 	}
-	//fmt.Sprintf("%v", gasRemainingGlobal)
+
+	exhausted := C.wasmer_metering_points_are_exhausted(self.inner())
+
+	runtime.KeepAlive(self)
+
+	return bool(exhausted), nil
+}
+
+// Interrupt makes the code running in the Instance trap at its next
+// metering point by exhausting the remaining points, and marks the
+// Instance as interrupted. It is meant to be called from another
+// goroutine, e.g. when a deadline expires; the Instance should be
+// discarded afterwards.
+//
+// The points are written while the code may be running and
+// decrementing them, so the write can be lost: call Interrupt again
+// until the call returns, and have the host functions trap once
+// Interrupted is true.
+//
+//   timer := time.AfterFunc(time.Second, func() {
+//       _ = instance.Interrupt()
+//   })
+//   defer timer.Stop()
+//   _, err := run()
+//
+func (self *Instance) Interrupt() error {
+	atomic.StoreInt32(&self.interrupted, 1)
+
+	return self.SetGasLimit(0)
+}
+
+// Interrupted returns true once Interrupt has been called.
+func (self *Instance) Interrupted() bool {
+	return atomic.LoadInt32(&self.interrupted) == 1
 }
@@ -0,0 +1,62 @@
+package wasmer
+
+import (
+	"bytes"
+	"sync"
+)
+
+// LogWriter is an io.Writer printing every line written to it with a
+// printf-like function, such as the Infof method of a zap
+// SugaredLogger. It routes the output of a WASI module to a logger.
+//
+//   wasiEnv, _ := NewWasiStateBuilder("test-program").
+//       CaptureStdoutTo(NewLogWriter(log.Infof)).
+//       CaptureStderrTo(NewLogWriter(log.Warnf)).
+//       Finalize()
+type LogWriter struct {
+	lock   sync.Mutex
+	printf func(template string, args ...interface{})
+	// The last line, until its end is written.
+	pending []byte
+}
+
+// NewLogWriter returns a LogWriter printing with printf.
+func NewLogWriter(printf func(template string, args ...interface{})) *LogWriter {
+	return &LogWriter{printf: printf}
+}
+
+// Write prints every complete line of p, without its line ending; the
+// last line is kept until its end is written or the LogWriter is
+// closed.
+func (self *LogWriter) Write(p []byte) (int, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	self.pending = append(self.pending, p...)
+
+	for {
+		end := bytes.IndexByte(self.pending, '\n')
+
+		if end < 0 {
+			break
+		}
+
+		self.printf("%s", bytes.TrimSuffix(self.pending[:end], []byte{'\r'}))
+		self.pending = self.pending[end+1:]
+	}
+
+	return len(p), nil
+}
+
+// Close prints the last line if it has no line ending.
+func (self *LogWriter) Close() error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if len(self.pending) > 0 {
+		self.printf("%s", self.pending)
+		self.pending = nil
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package wasmer
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLogWriter(t *testing.T) {
+	var lines []string
+	writer := NewLogWriter(func(template string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(template, args...))
+	})
+
+	_, err := writer.Write([]byte("first\nsec"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first"}, lines)
+
+	_, err = writer.Write([]byte("ond\r\n\nlast"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second", ""}, lines)
+
+	assert.NoError(t, writer.Close())
+	assert.Equal(t, []string{"first", "second", "", "last"}, lines)
+}
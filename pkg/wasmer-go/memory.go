@@ -3,6 +3,9 @@ package wasmer
 // #include <wasmer.h>
 import "C"
 import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
 	"reflect"
 	"runtime"
 	"unsafe"
@@ -93,6 +96,9 @@ func (self *Memory) DataSize() uint {
 
 // Data returns the Memory's contents as an byte array.
 //
+// The byte array aliases the Memory and is no longer valid after the
+// Memory grows; ReadAt, WriteAt and the other accessors are not.
+//
 //   memory, _ := instance.Exports.GetMemory("exported_memory")
 //   data := memory.Data()
 //
@@ -112,6 +118,128 @@ func (self *Memory) Data() []byte {
 	return *(*[]byte)(unsafe.Pointer(&header))
 }
 
+// The accessors below check the bounds against DataSize on every
+// call and copy the bytes, so they return an error instead of
+// panicking on an out of bounds access and stay valid after Grow,
+// unlike a slice returned by Data.
+
+// checkBounds returns an Error unless the length bytes at offset are
+// all in the Memory.
+func (self *Memory) checkBounds(offset int64, length int64) error {
+	size := int64(self.DataSize())
+
+	if offset < 0 || length < 0 || offset > size || length > size-offset {
+		return newErrorWith(fmt.Sprintf("memory access out of bounds, offset = %d, len = %d, size = %d", offset, length, size))
+	}
+
+	return nil
+}
+
+// ReadAt copies len(p) bytes at offset into p; it implements
+// io.ReaderAt. Nothing is read if any byte is out of bounds.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   bytes := make([]byte, 4)
+//   _, err := memory.ReadAt(bytes, 1024)
+//
+func (self *Memory) ReadAt(p []byte, offset int64) (int, error) {
+	if err := self.checkBounds(offset, int64(len(p))); err != nil {
+		return 0, err
+	}
+
+	n := copy(p, self.Data()[offset:])
+	runtime.KeepAlive(self)
+
+	return n, nil
+}
+
+// WriteAt copies p into the Memory at offset; it implements
+// io.WriterAt. Nothing is written if any byte is out of bounds.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   _, err := memory.WriteAt([]byte("hello"), 1024)
+//
+func (self *Memory) WriteAt(p []byte, offset int64) (int, error) {
+	if err := self.checkBounds(offset, int64(len(p))); err != nil {
+		return 0, err
+	}
+
+	n := copy(self.Data()[offset:], p)
+	runtime.KeepAlive(self)
+
+	return n, nil
+}
+
+// ReadSlice returns a copy of the length bytes at offset.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   bytes, err := memory.ReadSlice(1024, 5)
+//
+func (self *Memory) ReadSlice(offset int64, length int64) ([]byte, error) {
+	if err := self.checkBounds(offset, length); err != nil {
+		return nil, err
+	}
+
+	result := make([]byte, length)
+	copy(result, self.Data()[offset:])
+	runtime.KeepAlive(self)
+
+	return result, nil
+}
+
+// ReadUint32LE reads the little endian uint32 at offset.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   value, err := memory.ReadUint32LE(1024)
+//
+func (self *Memory) ReadUint32LE(offset int64) (uint32, error) {
+	var bytes [4]byte
+
+	if _, err := self.ReadAt(bytes[:], offset); err != nil {
+		return 0, err
+	}
+
+	return binary.LittleEndian.Uint32(bytes[:]), nil
+}
+
+// WriteUint32LE writes value as a little endian uint32 at offset.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   err := memory.WriteUint32LE(1024, 42)
+//
+func (self *Memory) WriteUint32LE(offset int64, value uint32) error {
+	var bytes [4]byte
+	binary.LittleEndian.PutUint32(bytes[:], value)
+
+	_, err := self.WriteAt(bytes[:], offset)
+
+	return err
+}
+
+// ReadCString reads the NUL-terminated string at offset, without the
+// NUL byte. It returns an Error if the Memory ends before a NUL byte.
+//
+//   memory, _ := instance.Exports.GetMemory("exported_memory")
+//   hello, err := memory.ReadCString(1024)
+//
+func (self *Memory) ReadCString(offset int64) (string, error) {
+	if err := self.checkBounds(offset, 0); err != nil {
+		return "", err
+	}
+
+	data := self.Data()[offset:]
+	end := bytes.IndexByte(data, 0)
+
+	if end < 0 {
+		return "", newErrorWith(fmt.Sprintf("memory access out of bounds, no NUL byte after offset = %d, size = %d", offset, self.DataSize()))
+	}
+
+	result := string(data[:end])
+	runtime.KeepAlive(self)
+
+	return result, nil
+}
+
 // Grow grows the Memory's size by a given number of Pages (the delta).
 //
 //   memory, _ := instance.Exports.GetMemory("exported_memory")
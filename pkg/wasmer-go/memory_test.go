@@ -86,3 +86,63 @@ func TestMemoryData(t *testing.T) {
 	assert.Equal(t, "Aello, World!", string(data2[pointer:pointer+13]))
 
 }
+
+func TestMemoryReadWriteAt(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	limits, err := NewLimits(1, 2)
+	assert.NoError(t, err)
+
+	memory := NewMemory(store, NewMemoryType(limits))
+
+	n, err := memory.WriteAt([]byte("hello\x00"), 16)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, n)
+
+	bytes, err := memory.ReadSlice(16, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), bytes)
+
+	hello, err := memory.ReadCString(16)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", hello)
+
+	assert.NoError(t, memory.WriteUint32LE(32, 0x01020304))
+	value, err := memory.ReadUint32LE(32)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x01020304), value)
+	assert.Equal(t, byte(0x04), memory.Data()[32])
+}
+
+func TestMemoryOutOfBounds(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	limits, err := NewLimits(1, 2)
+	assert.NoError(t, err)
+
+	memory := NewMemory(store, NewMemoryType(limits))
+
+	_, err = memory.WriteAt([]byte("hello"), 0x10000-4)
+	assert.Error(t, err)
+	assert.Equal(t, make([]byte, 4), memory.Data()[0x10000-4:])
+
+	_, err = memory.ReadSlice(-1, 4)
+	assert.Error(t, err)
+
+	_, err = memory.ReadUint32LE(0x10000 - 2)
+	assert.Error(t, err)
+
+	// no NUL byte until the end of the memory
+	_, err = memory.WriteAt([]byte("abc"), 0x10000-3)
+	assert.NoError(t, err)
+	_, err = memory.ReadCString(0x10000 - 3)
+	assert.Error(t, err)
+
+	// the bounds follow the memory when it grows
+	assert.True(t, memory.Grow(1))
+	_, err = memory.WriteAt([]byte("hello"), 0x10000-4)
+	assert.NoError(t, err)
+	hello, err := memory.ReadSlice(0x10000-4, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("hello"), hello)
+}
@@ -0,0 +1,247 @@
+package wasmer
+
+// #include <wasmer.h>
+import "C"
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"sort"
+)
+
+// OperatorClass groups the operators a Metering charges alike.
+type OperatorClass uint8
+
+const (
+	// Blocks, branches, `return`, `nop` and `unreachable`.
+	OperatorControl OperatorClass = iota
+	// `call`, `call_indirect` and their tail call variants.
+	OperatorCall
+	// Locals, globals, `drop` and `select`.
+	OperatorVariable
+	// Loads from the linear memory.
+	OperatorLoad
+	// Stores to the linear memory.
+	OperatorStore
+	// `memory.size`, `memory.grow`, bulk memory and table operators.
+	OperatorMemory
+	// Integer constants, arithmetic, comparisons and conversions.
+	OperatorInteger
+	// Float constants, arithmetic, comparisons and any conversion
+	// from or to a float.
+	OperatorFloat
+	// SIMD operators, apart from loads and stores.
+	OperatorVector
+	// Atomic operators of the threads proposal.
+	OperatorAtomic
+	// `ref.null`, `ref.is_null` and `ref.func`.
+	OperatorReference
+)
+
+// String returns the OperatorClass as a string.
+//
+//   OperatorLoad.String() // "load"
+func (self OperatorClass) String() string {
+	switch self {
+	case OperatorControl:
+		return "control"
+	case OperatorCall:
+		return "call"
+	case OperatorVariable:
+		return "variable"
+	case OperatorLoad:
+		return "load"
+	case OperatorStore:
+		return "store"
+	case OperatorMemory:
+		return "memory"
+	case OperatorInteger:
+		return "integer"
+	case OperatorFloat:
+		return "float"
+	case OperatorVector:
+		return "vector"
+	case OperatorAtomic:
+		return "atomic"
+	case OperatorReference:
+		return "reference"
+	}
+	return fmt.Sprintf("OperatorClass(%d)", uint8(self))
+}
+
+// Class returns the OperatorClass of the Opcode.
+//
+//   OpI32Load.Class() // OperatorLoad
+func (self Opcode) Class() OperatorClass {
+	return opcodeClasses[self]
+}
+
+// Metering is the cost model of the metering middleware: how many
+// points each operator costs, and how many points an Instance starts
+// with.
+//
+// The cost of an operator is, by order of precedence, the result of
+// CostFunction, its entry in OpcodeCosts, the entry of its class in
+// ClassCosts, then DefaultCost.
+//
+//   metering := &Metering{
+//       InitialLimit: 10_000_000,
+//       ClassCosts: map[OperatorClass]uint64{
+//           OperatorLoad:  3,
+//           OperatorStore: 3,
+//           OperatorCall:  10,
+//       },
+//       OpcodeCosts: map[Opcode]uint64{OpMemoryGrow: 1000},
+//       DefaultCost: 1,
+//   }
+//   config := NewConfig().PushMeteringMiddleware(metering)
+type Metering struct {
+	// Points an Instance starts with, see Instance.SetGasLimit.
+	InitialLimit uint64
+	// Costs of single operators.
+	OpcodeCosts map[Opcode]uint64
+	// Costs of operator classes.
+	ClassCosts map[OperatorClass]uint64
+	// Cost of operators found in neither table.
+	DefaultCost uint64
+	// Computes the cost of every operator when set.
+	CostFunction func(opcode Opcode, class OperatorClass) uint64
+}
+
+// Cost returns the points the operator costs.
+func (self *Metering) Cost(opcode Opcode) uint64 {
+	class := opcode.Class()
+
+	if self.CostFunction != nil {
+		return self.CostFunction(opcode, class)
+	}
+
+	if cost, ok := self.OpcodeCosts[opcode]; ok {
+		return cost
+	}
+
+	if cost, ok := self.ClassCosts[class]; ok {
+		return cost
+	}
+
+	return self.DefaultCost
+}
+
+// meteringModel is the cost of every operator under a Metering,
+// computed when an Engine is created: changing the Metering later
+// changes none of the Engines created before.
+type meteringModel struct {
+	costs        map[Opcode]uint64
+	defaultCost  uint64
+	initialLimit uint64
+	// Identifies the costs and the initial limit.
+	digest string
+	// The cost function slot of the model, and the number of Engines
+	// using it, see acquireMeteringModel.
+	slot    int
+	engines int
+}
+
+func newMeteringModel(metering *Metering) *meteringModel {
+	self := &meteringModel{
+		costs:        make(map[Opcode]uint64, len(opcodeClasses)),
+		defaultCost:  metering.DefaultCost,
+		initialLimit: metering.InitialLimit,
+	}
+
+	opcodes := make([]Opcode, 0, len(opcodeClasses))
+
+	for opcode := range opcodeClasses {
+		opcodes = append(opcodes, opcode)
+	}
+
+	sort.Slice(opcodes, func(i, j int) bool { return opcodes[i] < opcodes[j] })
+
+	hash := sha256.New()
+	var buffer [8]byte
+
+	for _, value := range []uint64{self.initialLimit, self.defaultCost} {
+		binary.LittleEndian.PutUint64(buffer[:], value)
+		hash.Write(buffer[:])
+	}
+
+	for _, opcode := range opcodes {
+		cost := metering.Cost(opcode)
+		self.costs[opcode] = cost
+
+		binary.LittleEndian.PutUint64(buffer[:], uint64(opcode))
+		hash.Write(buffer[:])
+		binary.LittleEndian.PutUint64(buffer[:], cost)
+		hash.Write(buffer[:])
+	}
+
+	self.digest = hex.EncodeToString(hash.Sum(nil))
+
+	return self
+}
+
+func (self *meteringModel) cost(opcode Opcode) uint64 {
+	if cost, ok := self.costs[opcode]; ok {
+		return cost
+	}
+
+	return self.defaultCost
+}
+
+//export metering_cost_delegate
+func metering_cost_delegate(slot C.uint32_t, operator C.enum_wasmer_parser_operator_t) C.uint64_t {
+	model := meteringModelOfSlot(int(slot))
+
+	if model == nil {
+		return 0
+	}
+
+	return C.uint64_t(model.cost(Opcode(operator)))
+}
+
+// PushMeteringMiddleware adds a metering middleware charging the
+// operators according to the Metering. Every Engine created from the
+// Config, and every Module compiled by it, uses this cost model; so a
+// chain can price operators differently from one block version to
+// the next by using one Engine per version.
+//
+// The costs are read from the Metering when the Engine is created,
+// each Engine keeps its own copy: Engines with different cost models
+// can compile modules at the same time, up to 64 models at once.
+//
+//   config := NewConfig().PushMeteringMiddleware(metering)
+//   engine, err := NewEngineWithConfig(config)
+func (self *Config) PushMeteringMiddleware(metering *Metering) *Config {
+	self.metering = metering
+
+	return self
+}
+
+// metered returns an Error if the instance isn't metered: the native
+// metering functions look the metering globals up, and abort the
+// process if they are missing.
+func (self *Exports) metered() error {
+	for _, name := range []string{"wasmer_metering_remaining_points", "wasmer_metering_points_exhausted"} {
+		if _, exists := self.exports[name]; !exists {
+			return newErrorWith(fmt.Sprintf("The instance is not metered, `%s` is not exported", name))
+		}
+	}
+
+	return nil
+}
+
+// meteringPointsExhausted returns true if the instance is metered and
+// ran out of points.
+func (self *Exports) meteringPointsExhausted() bool {
+	if self.metered() != nil {
+		return false
+	}
+
+	exhausted := C.wasmer_metering_points_are_exhausted(self.instance)
+
+	runtime.KeepAlive(self)
+
+	return bool(exhausted)
+}
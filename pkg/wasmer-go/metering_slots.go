@@ -0,0 +1,176 @@
+package wasmer
+
+// #include <wasmer.h>
+//
+// extern uint64_t metering_cost_delegate(uint32_t slot, enum wasmer_parser_operator_t wasm_operator);
+//
+// // One cost function per slot, the cost function of the C API carries
+// // no environment.
+// #define METERING_COST_FUNCTION(slot) \
+//     static uint64_t metering_cost_##slot(enum wasmer_parser_operator_t wasm_operator) { \
+//         return metering_cost_delegate(slot, wasm_operator); \
+//     }
+//
+// METERING_COST_FUNCTION(0)
+// METERING_COST_FUNCTION(1)
+// METERING_COST_FUNCTION(2)
+// METERING_COST_FUNCTION(3)
+// METERING_COST_FUNCTION(4)
+// METERING_COST_FUNCTION(5)
+// METERING_COST_FUNCTION(6)
+// METERING_COST_FUNCTION(7)
+// METERING_COST_FUNCTION(8)
+// METERING_COST_FUNCTION(9)
+// METERING_COST_FUNCTION(10)
+// METERING_COST_FUNCTION(11)
+// METERING_COST_FUNCTION(12)
+// METERING_COST_FUNCTION(13)
+// METERING_COST_FUNCTION(14)
+// METERING_COST_FUNCTION(15)
+// METERING_COST_FUNCTION(16)
+// METERING_COST_FUNCTION(17)
+// METERING_COST_FUNCTION(18)
+// METERING_COST_FUNCTION(19)
+// METERING_COST_FUNCTION(20)
+// METERING_COST_FUNCTION(21)
+// METERING_COST_FUNCTION(22)
+// METERING_COST_FUNCTION(23)
+// METERING_COST_FUNCTION(24)
+// METERING_COST_FUNCTION(25)
+// METERING_COST_FUNCTION(26)
+// METERING_COST_FUNCTION(27)
+// METERING_COST_FUNCTION(28)
+// METERING_COST_FUNCTION(29)
+// METERING_COST_FUNCTION(30)
+// METERING_COST_FUNCTION(31)
+// METERING_COST_FUNCTION(32)
+// METERING_COST_FUNCTION(33)
+// METERING_COST_FUNCTION(34)
+// METERING_COST_FUNCTION(35)
+// METERING_COST_FUNCTION(36)
+// METERING_COST_FUNCTION(37)
+// METERING_COST_FUNCTION(38)
+// METERING_COST_FUNCTION(39)
+// METERING_COST_FUNCTION(40)
+// METERING_COST_FUNCTION(41)
+// METERING_COST_FUNCTION(42)
+// METERING_COST_FUNCTION(43)
+// METERING_COST_FUNCTION(44)
+// METERING_COST_FUNCTION(45)
+// METERING_COST_FUNCTION(46)
+// METERING_COST_FUNCTION(47)
+// METERING_COST_FUNCTION(48)
+// METERING_COST_FUNCTION(49)
+// METERING_COST_FUNCTION(50)
+// METERING_COST_FUNCTION(51)
+// METERING_COST_FUNCTION(52)
+// METERING_COST_FUNCTION(53)
+// METERING_COST_FUNCTION(54)
+// METERING_COST_FUNCTION(55)
+// METERING_COST_FUNCTION(56)
+// METERING_COST_FUNCTION(57)
+// METERING_COST_FUNCTION(58)
+// METERING_COST_FUNCTION(59)
+// METERING_COST_FUNCTION(60)
+// METERING_COST_FUNCTION(61)
+// METERING_COST_FUNCTION(62)
+// METERING_COST_FUNCTION(63)
+//
+// static wasmer_metering_cost_function_t metering_cost_functions[] = {
+//     metering_cost_0, metering_cost_1, metering_cost_2, metering_cost_3,
+//     metering_cost_4, metering_cost_5, metering_cost_6, metering_cost_7,
+//     metering_cost_8, metering_cost_9, metering_cost_10, metering_cost_11,
+//     metering_cost_12, metering_cost_13, metering_cost_14, metering_cost_15,
+//     metering_cost_16, metering_cost_17, metering_cost_18, metering_cost_19,
+//     metering_cost_20, metering_cost_21, metering_cost_22, metering_cost_23,
+//     metering_cost_24, metering_cost_25, metering_cost_26, metering_cost_27,
+//     metering_cost_28, metering_cost_29, metering_cost_30, metering_cost_31,
+//     metering_cost_32, metering_cost_33, metering_cost_34, metering_cost_35,
+//     metering_cost_36, metering_cost_37, metering_cost_38, metering_cost_39,
+//     metering_cost_40, metering_cost_41, metering_cost_42, metering_cost_43,
+//     metering_cost_44, metering_cost_45, metering_cost_46, metering_cost_47,
+//     metering_cost_48, metering_cost_49, metering_cost_50, metering_cost_51,
+//     metering_cost_52, metering_cost_53, metering_cost_54, metering_cost_55,
+//     metering_cost_56, metering_cost_57, metering_cost_58, metering_cost_59,
+//     metering_cost_60, metering_cost_61, metering_cost_62, metering_cost_63,
+// };
+//
+// static struct wasmer_middleware_t* to_wasmer_metering_middleware(uint64_t initial_limit, uint32_t slot) {
+//     return wasmer_metering_as_middleware(wasmer_metering_new(initial_limit, metering_cost_functions[slot]));
+// }
+import "C"
+import (
+	"fmt"
+	"sync"
+)
+
+// meteringSlots is the number of cost models that can be used at
+// once, by as many Engines as needed: Engines with the same model
+// share its slot.
+const meteringSlots = 64
+
+var meteringSlotsLock sync.RWMutex
+var meteringModels [meteringSlots]*meteringModel
+var meteringModelsByDigest = make(map[string]*meteringModel)
+
+// acquireMeteringModel returns the model of the Metering, bound to a
+// cost function slot, for a new Engine; releaseMeteringModel gives it
+// back when the Engine is deleted. It returns an error if every slot
+// is used by another model.
+func acquireMeteringModel(metering *Metering) (*meteringModel, error) {
+	model := newMeteringModel(metering)
+
+	meteringSlotsLock.Lock()
+	defer meteringSlotsLock.Unlock()
+
+	if existing, exists := meteringModelsByDigest[model.digest]; exists {
+		existing.engines++
+
+		return existing, nil
+	}
+
+	for slot, used := range meteringModels {
+		if used == nil {
+			model.slot = slot
+			model.engines = 1
+			meteringModels[slot] = model
+			meteringModelsByDigest[model.digest] = model
+
+			return model, nil
+		}
+	}
+
+	return nil, newErrorWith(fmt.Sprintf("Too many metering cost models are in use; at most %d Engines with different models can exist at once", meteringSlots))
+}
+
+func releaseMeteringModel(model *meteringModel) {
+	meteringSlotsLock.Lock()
+	defer meteringSlotsLock.Unlock()
+
+	model.engines--
+
+	if model.engines == 0 {
+		meteringModels[model.slot] = nil
+		delete(meteringModelsByDigest, model.digest)
+	}
+}
+
+func meteringModelOfSlot(slot int) *meteringModel {
+	meteringSlotsLock.RLock()
+	defer meteringSlotsLock.RUnlock()
+
+	if slot < 0 || slot >= meteringSlots {
+		return nil
+	}
+
+	return meteringModels[slot]
+}
+
+// pushMeteringMiddleware adds the metering middleware of the model to
+// the C configuration.
+func pushMeteringMiddleware(config *C.wasm_config_t, model *meteringModel) {
+	C.wasm_config_push_middleware(
+		config,
+		C.to_wasmer_metering_middleware(C.uint64_t(model.initialLimit), C.uint32_t(model.slot)),
+	)
+}
@@ -0,0 +1,159 @@
+package wasmer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOpcodeClass(t *testing.T) {
+	assert.Equal(t, OperatorLoad, OpI32Load.Class())
+	assert.Equal(t, OperatorStore, OpF64Store.Class())
+	assert.Equal(t, OperatorCall, OpCallIndirect.Class())
+	assert.Equal(t, OperatorFloat, OpI32TruncF32S.Class())
+	assert.Equal(t, OperatorInteger, OpI64Add.Class())
+	assert.Equal(t, OperatorAtomic, OpI32AtomicLoad.Class())
+	assert.Equal(t, OperatorMemory, OpMemoryGrow.Class())
+	assert.Equal(t, "load", OperatorLoad.String())
+}
+
+func TestMeteringCost(t *testing.T) {
+	metering := &Metering{
+		OpcodeCosts: map[Opcode]uint64{OpMemoryGrow: 1000},
+		ClassCosts:  map[OperatorClass]uint64{OperatorMemory: 50, OperatorCall: 10},
+		DefaultCost: 1,
+	}
+	assert.Equal(t, uint64(1000), metering.Cost(OpMemoryGrow))
+	assert.Equal(t, uint64(50), metering.Cost(OpMemorySize))
+	assert.Equal(t, uint64(10), metering.Cost(OpCall))
+	assert.Equal(t, uint64(1), metering.Cost(OpI32Add))
+
+	metering.CostFunction = func(opcode Opcode, class OperatorClass) uint64 {
+		return 7
+	}
+	assert.Equal(t, uint64(7), metering.Cost(OpMemoryGrow))
+}
+
+func TestMeteringMiddleware(t *testing.T) {
+	metering := &Metering{
+		InitialLimit: 1000,
+		ClassCosts:   map[OperatorClass]uint64{OperatorInteger: 10},
+		DefaultCost:  1,
+	}
+	engine, err := NewEngineWithConfig(NewConfig().PushMeteringMiddleware(metering))
+	assert.NoError(t, err)
+	store := NewStore(engine)
+	module, err := NewModule(store, testGetBytes("tests.wasm"), nil)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, NewImportObject())
+	assert.NoError(t, err)
+
+	assert.NoError(t, instance.SetGasLimit(metering.InitialLimit))
+	sum, err := instance.Exports.GetFunction("sum")
+	assert.NoError(t, err)
+
+	result, err := sum(37, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(42), result)
+	remaining, err := instance.GetGasRemaining()
+	assert.NoError(t, err)
+	assert.Less(t, remaining, metering.InitialLimit)
+
+	exhausted, err := instance.PointsExhausted()
+	assert.NoError(t, err)
+	assert.False(t, exhausted)
+
+	// sum costs more than 10 points
+	assert.NoError(t, instance.SetGasLimit(10))
+	_, err = sum(37, 5)
+	assert.Error(t, err)
+
+	remaining, err = instance.GetGasRemaining()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), remaining)
+
+	exhausted, err = instance.PointsExhausted()
+	assert.NoError(t, err)
+	assert.True(t, exhausted)
+
+	// a new limit resets the exhausted state
+	assert.NoError(t, instance.SetGasLimit(metering.InitialLimit))
+	exhausted, err = instance.PointsExhausted()
+	assert.NoError(t, err)
+	assert.False(t, exhausted)
+
+	// an interrupted instance stays interrupted
+	assert.False(t, instance.Interrupted())
+	assert.NoError(t, instance.Interrupt())
+	_, err = sum(37, 5)
+	assert.Error(t, err)
+	assert.NoError(t, instance.SetGasLimit(metering.InitialLimit))
+	assert.True(t, instance.Interrupted())
+}
+
+func TestMeteringNotMetered(t *testing.T) {
+	instance := testGetInstance(t)
+
+	_, err := instance.GetGasRemaining()
+	assert.Error(t, err)
+	assert.Error(t, instance.SetGasLimit(100))
+	assert.Error(t, instance.Interrupt())
+
+	_, err = instance.PointsExhausted()
+	assert.Error(t, err)
+}
+
+func TestMeteringModel(t *testing.T) {
+	metering := &Metering{InitialLimit: 1000, DefaultCost: 1}
+	model, err := acquireMeteringModel(metering)
+	assert.NoError(t, err)
+	defer releaseMeteringModel(model)
+
+	// the model is a copy, Engines with the same costs share it
+	metering.DefaultCost = 2
+	assert.Equal(t, uint64(1), model.cost(OpI32Add))
+
+	other, err := acquireMeteringModel(metering)
+	assert.NoError(t, err)
+	assert.NotEqual(t, model.digest, other.digest)
+	assert.NotEqual(t, model.slot, other.slot)
+	releaseMeteringModel(other)
+
+	metering.DefaultCost = 1
+	same, err := acquireMeteringModel(metering)
+	assert.NoError(t, err)
+	assert.Equal(t, model, same)
+	assert.Equal(t, 2, model.engines)
+	releaseMeteringModel(same)
+}
+
+func TestMeteringModelSlotsExhausted(t *testing.T) {
+	var models []*meteringModel
+
+	defer func() {
+		for _, model := range models {
+			releaseMeteringModel(model)
+		}
+	}()
+
+	for cost := uint64(1); len(models) < meteringSlots; cost++ {
+		model, err := acquireMeteringModel(&Metering{DefaultCost: cost})
+
+		if err != nil {
+			break
+		}
+
+		models = append(models, model)
+	}
+
+	// every slot is used, another model fails but a used one is shared
+	_, err := acquireMeteringModel(&Metering{DefaultCost: 1000})
+	assert.Error(t, err)
+
+	_, err = NewEngineWithConfig(NewConfig().PushMeteringMiddleware(&Metering{DefaultCost: 1000}))
+	assert.Error(t, err)
+
+	model, err := acquireMeteringModel(&Metering{DefaultCost: 1})
+	assert.NoError(t, err)
+	releaseMeteringModel(model)
+}
@@ -59,6 +59,9 @@ type Module struct {
 	importTypes *importTypes
 	// Stored if computed to avoid further reallocations.
 	exportTypes *exportTypes
+	// The function names and source locations, nil if the module
+	// was deserialized by DeserializeModule.
+	symbols *moduleSymbols
 }
 
 var lock sync.Mutex
@@ -66,22 +69,59 @@ var lock sync.Mutex
 // NewModule instantiates a new Module with the given Store.
 //
 // It takes two arguments, the Store and the Wasm module as a byte
-// array of WAT code.
+// array of either binary Wasm, detected by its `\0asm` magic, or WAT
+// code.
 //
 //	wasmBytes := []byte(`...`)
 //	engine := wasmer.NewEngine()
 //	store := wasmer.NewStore(engine)
 //	module, err := wasmer.NewModule(store, wasmBytes)
 func NewModule(store *Store, bytes []byte, log *logger.CMLogger) (*Module, error) {
-	lock.Lock()
-	defer lock.Unlock()
+	wasmBytes, err := toWasm(bytes)
 
-	wasmBytes, err := Wat2Wasm(string(bytes))
+	if err != nil {
+		return nil, err
+	}
+
+	return newModule(store, wasmBytes, log)
+}
+
+// NewModuleFromWat instantiates a new Module with the given Store
+// from WAT code. A parse error is returned as a WatError when its
+// position is known.
+//
+//	engine := wasmer.NewEngine()
+//	store := wasmer.NewStore(engine)
+//	module, err := wasmer.NewModuleFromWat(store, "(module)", nil)
+func NewModuleFromWat(store *Store, wat string, log *logger.CMLogger) (*Module, error) {
+	wasmBytes, err := wat2Wasm([]byte(wat))
 
 	if err != nil {
 		return nil, err
 	}
 
+	return newModule(store, wasmBytes, log)
+}
+
+// NewModuleFromBinary instantiates a new Module with the given Store
+// from binary Wasm, which is never parsed as WAT code.
+//
+//	wasmBytes, _ := ioutil.ReadFile("module.wasm")
+//	engine := wasmer.NewEngine()
+//	store := wasmer.NewStore(engine)
+//	module, err := wasmer.NewModuleFromBinary(store, wasmBytes, nil)
+func NewModuleFromBinary(store *Store, wasmBytes []byte, log *logger.CMLogger) (*Module, error) {
+	if !IsWasmBinary(wasmBytes) {
+		return nil, newErrorWith("bytes are not a binary Wasm module, the `\\0asm` magic is missing")
+	}
+
+	return newModule(store, wasmBytes, log)
+}
+
+func newModule(store *Store, wasmBytes []byte, log *logger.CMLogger) (*Module, error) {
+	lock.Lock()
+	defer lock.Unlock()
+
 	var wasmBytesPtr *C.uint8_t
 	wasmBytesLength := len(wasmBytes)
 
@@ -99,8 +139,9 @@ func NewModule(store *Store, bytes []byte, log *logger.CMLogger) (*Module, error
 		}
 		inner := C.to_wasm_module_new(store.inner(), wasmBytesPtr, C.size_t(wasmBytesLength))
 		self = &Module{
-			_inner: inner,
-			store:  store,
+			_inner:  inner,
+			store:   store,
+			symbols: newModuleSymbols(wasmBytes),
 		}
 
 		return self._inner == nil
@@ -110,6 +151,7 @@ func NewModule(store *Store, bytes []byte, log *logger.CMLogger) (*Module, error
 		return nil, err2
 	}
 
+	runtime.KeepAlive(wasmBytes)
 	runtime.SetFinalizer(self, func(self *Module) {
 		self.Close()
 	})
@@ -120,8 +162,9 @@ func NewModule(store *Store, bytes []byte, log *logger.CMLogger) (*Module, error
 // ValidateModule validates a new Module against the given Store.
 //
 // It takes two arguments, the Store and the WebAssembly module as a
-// byte array. The function returns an error describing why the bytes
-// are invalid, otherwise it returns nil.
+// byte array of either binary Wasm or WAT code. The function returns
+// an error describing why the bytes are invalid, otherwise it returns
+// nil.
 //
 //	wasmBytes := []byte(`...`)
 //	engine := wasmer.NewEngine()
@@ -130,7 +173,7 @@ func NewModule(store *Store, bytes []byte, log *logger.CMLogger) (*Module, error
 //
 //	isValid := err != nil
 func ValidateModule(store *Store, bytes []byte) error {
-	wasmBytes, err := Wat2Wasm(string(bytes))
+	wasmBytes, err := toWasm(bytes)
 
 	if err != nil {
 		return err
@@ -277,6 +320,27 @@ func DeserializeModule(store *Store, bytes []byte) (*Module, error) {
 	return self, nil
 }
 
+// DeserializeModuleWithSymbols deserializes an byte array to a Module
+// like DeserializeModule, and reads the names and the source locations
+// of its functions from wasmBytes, the module it was compiled from: the
+// frames of its traps are then resolved as if it was compiled by
+// NewModule.
+//
+//   module, _ := wasmer.DeserializeModuleWithSymbols(store, bytes, wasmBytes)
+func DeserializeModuleWithSymbols(store *Store, bytes []byte, wasmBytes []byte) (*Module, error) {
+	self, err := DeserializeModule(store, bytes)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if wasmBytes, err = toWasm(wasmBytes); err == nil {
+		self.symbols = newModuleSymbols(wasmBytes)
+	}
+
+	return self, nil
+}
+
 // Force to close the Module.
 //
 // A runtime finalizer is registered on the Module, but it is possible
@@ -12,6 +12,25 @@ func TestModule(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestModuleFromWatAndBinary(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+
+	_, err := NewModuleFromWat(store, "(module)", nil)
+	assert.NoError(t, err)
+
+	_, err = NewModuleFromBinary(store, []byte("\x00asm\x01\x00\x00\x00"), nil)
+	assert.NoError(t, err)
+
+	_, err = NewModuleFromBinary(store, []byte("(module)"), nil)
+	assert.Error(t, err)
+
+	_, err = NewModuleFromWat(store, "(module\n  (func (result i32)\n    i32.const))", nil)
+	watErr, ok := err.(*WatError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, watErr.Line())
+}
+
 func TestValidateModule(t *testing.T) {
 	engine := NewEngine()
 	store := NewStore(engine)
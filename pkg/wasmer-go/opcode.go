@@ -0,0 +1,1042 @@
+package wasmer
+
+// #include <wasmer.h>
+import "C"
+
+// Opcode represents a WebAssembly operator, as seen by the metering
+// middleware. The constants below follow the
+// `wasmer_parser_operator_t` enum of `wasmer.h`.
+type Opcode C.wasmer_parser_operator_t
+
+const (
+	OpUnreachable               = Opcode(C.Unreachable)
+	OpNop                       = Opcode(C.Nop)
+	OpBlock                     = Opcode(C.Block)
+	OpLoop                      = Opcode(C.Loop)
+	OpIf                        = Opcode(C.If)
+	OpElse                      = Opcode(C.Else)
+	OpTry                       = Opcode(C.Try)
+	OpCatch                     = Opcode(C.Catch)
+	OpCatchAll                  = Opcode(C.CatchAll)
+	OpDelegate                  = Opcode(C.Delegate)
+	OpThrow                     = Opcode(C.Throw)
+	OpRethrow                   = Opcode(C.Rethrow)
+	OpUnwind                    = Opcode(C.Unwind)
+	OpEnd                       = Opcode(C.End)
+	OpBr                        = Opcode(C.Br)
+	OpBrIf                      = Opcode(C.BrIf)
+	OpBrTable                   = Opcode(C.BrTable)
+	OpReturn                    = Opcode(C.Return)
+	OpCall                      = Opcode(C.Call)
+	OpCallIndirect              = Opcode(C.CallIndirect)
+	OpReturnCall                = Opcode(C.ReturnCall)
+	OpReturnCallIndirect        = Opcode(C.ReturnCallIndirect)
+	OpDrop                      = Opcode(C.Drop)
+	OpSelect                    = Opcode(C.Select)
+	OpTypedSelect               = Opcode(C.TypedSelect)
+	OpLocalGet                  = Opcode(C.LocalGet)
+	OpLocalSet                  = Opcode(C.LocalSet)
+	OpLocalTee                  = Opcode(C.LocalTee)
+	OpGlobalGet                 = Opcode(C.GlobalGet)
+	OpGlobalSet                 = Opcode(C.GlobalSet)
+	OpI32Load                   = Opcode(C.I32Load)
+	OpI64Load                   = Opcode(C.I64Load)
+	OpF32Load                   = Opcode(C.F32Load)
+	OpF64Load                   = Opcode(C.F64Load)
+	OpI32Load8S                 = Opcode(C.I32Load8S)
+	OpI32Load8U                 = Opcode(C.I32Load8U)
+	OpI32Load16S                = Opcode(C.I32Load16S)
+	OpI32Load16U                = Opcode(C.I32Load16U)
+	OpI64Load8S                 = Opcode(C.I64Load8S)
+	OpI64Load8U                 = Opcode(C.I64Load8U)
+	OpI64Load16S                = Opcode(C.I64Load16S)
+	OpI64Load16U                = Opcode(C.I64Load16U)
+	OpI64Load32S                = Opcode(C.I64Load32S)
+	OpI64Load32U                = Opcode(C.I64Load32U)
+	OpI32Store                  = Opcode(C.I32Store)
+	OpI64Store                  = Opcode(C.I64Store)
+	OpF32Store                  = Opcode(C.F32Store)
+	OpF64Store                  = Opcode(C.F64Store)
+	OpI32Store8                 = Opcode(C.I32Store8)
+	OpI32Store16                = Opcode(C.I32Store16)
+	OpI64Store8                 = Opcode(C.I64Store8)
+	OpI64Store16                = Opcode(C.I64Store16)
+	OpI64Store32                = Opcode(C.I64Store32)
+	OpMemorySize                = Opcode(C.MemorySize)
+	OpMemoryGrow                = Opcode(C.MemoryGrow)
+	OpI32Const                  = Opcode(C.I32Const)
+	OpI64Const                  = Opcode(C.I64Const)
+	OpF32Const                  = Opcode(C.F32Const)
+	OpF64Const                  = Opcode(C.F64Const)
+	OpRefNull                   = Opcode(C.RefNull)
+	OpRefIsNull                 = Opcode(C.RefIsNull)
+	OpRefFunc                   = Opcode(C.RefFunc)
+	OpI32Eqz                    = Opcode(C.I32Eqz)
+	OpI32Eq                     = Opcode(C.I32Eq)
+	OpI32Ne                     = Opcode(C.I32Ne)
+	OpI32LtS                    = Opcode(C.I32LtS)
+	OpI32LtU                    = Opcode(C.I32LtU)
+	OpI32GtS                    = Opcode(C.I32GtS)
+	OpI32GtU                    = Opcode(C.I32GtU)
+	OpI32LeS                    = Opcode(C.I32LeS)
+	OpI32LeU                    = Opcode(C.I32LeU)
+	OpI32GeS                    = Opcode(C.I32GeS)
+	OpI32GeU                    = Opcode(C.I32GeU)
+	OpI64Eqz                    = Opcode(C.I64Eqz)
+	OpI64Eq                     = Opcode(C.I64Eq)
+	OpI64Ne                     = Opcode(C.I64Ne)
+	OpI64LtS                    = Opcode(C.I64LtS)
+	OpI64LtU                    = Opcode(C.I64LtU)
+	OpI64GtS                    = Opcode(C.I64GtS)
+	OpI64GtU                    = Opcode(C.I64GtU)
+	OpI64LeS                    = Opcode(C.I64LeS)
+	OpI64LeU                    = Opcode(C.I64LeU)
+	OpI64GeS                    = Opcode(C.I64GeS)
+	OpI64GeU                    = Opcode(C.I64GeU)
+	OpF32Eq                     = Opcode(C.F32Eq)
+	OpF32Ne                     = Opcode(C.F32Ne)
+	OpF32Lt                     = Opcode(C.F32Lt)
+	OpF32Gt                     = Opcode(C.F32Gt)
+	OpF32Le                     = Opcode(C.F32Le)
+	OpF32Ge                     = Opcode(C.F32Ge)
+	OpF64Eq                     = Opcode(C.F64Eq)
+	OpF64Ne                     = Opcode(C.F64Ne)
+	OpF64Lt                     = Opcode(C.F64Lt)
+	OpF64Gt                     = Opcode(C.F64Gt)
+	OpF64Le                     = Opcode(C.F64Le)
+	OpF64Ge                     = Opcode(C.F64Ge)
+	OpI32Clz                    = Opcode(C.I32Clz)
+	OpI32Ctz                    = Opcode(C.I32Ctz)
+	OpI32Popcnt                 = Opcode(C.I32Popcnt)
+	OpI32Add                    = Opcode(C.I32Add)
+	OpI32Sub                    = Opcode(C.I32Sub)
+	OpI32Mul                    = Opcode(C.I32Mul)
+	OpI32DivS                   = Opcode(C.I32DivS)
+	OpI32DivU                   = Opcode(C.I32DivU)
+	OpI32RemS                   = Opcode(C.I32RemS)
+	OpI32RemU                   = Opcode(C.I32RemU)
+	OpI32And                    = Opcode(C.I32And)
+	OpI32Or                     = Opcode(C.I32Or)
+	OpI32Xor                    = Opcode(C.I32Xor)
+	OpI32Shl                    = Opcode(C.I32Shl)
+	OpI32ShrS                   = Opcode(C.I32ShrS)
+	OpI32ShrU                   = Opcode(C.I32ShrU)
+	OpI32Rotl                   = Opcode(C.I32Rotl)
+	OpI32Rotr                   = Opcode(C.I32Rotr)
+	OpI64Clz                    = Opcode(C.I64Clz)
+	OpI64Ctz                    = Opcode(C.I64Ctz)
+	OpI64Popcnt                 = Opcode(C.I64Popcnt)
+	OpI64Add                    = Opcode(C.I64Add)
+	OpI64Sub                    = Opcode(C.I64Sub)
+	OpI64Mul                    = Opcode(C.I64Mul)
+	OpI64DivS                   = Opcode(C.I64DivS)
+	OpI64DivU                   = Opcode(C.I64DivU)
+	OpI64RemS                   = Opcode(C.I64RemS)
+	OpI64RemU                   = Opcode(C.I64RemU)
+	OpI64And                    = Opcode(C.I64And)
+	OpI64Or                     = Opcode(C.I64Or)
+	OpI64Xor                    = Opcode(C.I64Xor)
+	OpI64Shl                    = Opcode(C.I64Shl)
+	OpI64ShrS                   = Opcode(C.I64ShrS)
+	OpI64ShrU                   = Opcode(C.I64ShrU)
+	OpI64Rotl                   = Opcode(C.I64Rotl)
+	OpI64Rotr                   = Opcode(C.I64Rotr)
+	OpF32Abs                    = Opcode(C.F32Abs)
+	OpF32Neg                    = Opcode(C.F32Neg)
+	OpF32Ceil                   = Opcode(C.F32Ceil)
+	OpF32Floor                  = Opcode(C.F32Floor)
+	OpF32Trunc                  = Opcode(C.F32Trunc)
+	OpF32Nearest                = Opcode(C.F32Nearest)
+	OpF32Sqrt                   = Opcode(C.F32Sqrt)
+	OpF32Add                    = Opcode(C.F32Add)
+	OpF32Sub                    = Opcode(C.F32Sub)
+	OpF32Mul                    = Opcode(C.F32Mul)
+	OpF32Div                    = Opcode(C.F32Div)
+	OpF32Min                    = Opcode(C.F32Min)
+	OpF32Max                    = Opcode(C.F32Max)
+	OpF32Copysign               = Opcode(C.F32Copysign)
+	OpF64Abs                    = Opcode(C.F64Abs)
+	OpF64Neg                    = Opcode(C.F64Neg)
+	OpF64Ceil                   = Opcode(C.F64Ceil)
+	OpF64Floor                  = Opcode(C.F64Floor)
+	OpF64Trunc                  = Opcode(C.F64Trunc)
+	OpF64Nearest                = Opcode(C.F64Nearest)
+	OpF64Sqrt                   = Opcode(C.F64Sqrt)
+	OpF64Add                    = Opcode(C.F64Add)
+	OpF64Sub                    = Opcode(C.F64Sub)
+	OpF64Mul                    = Opcode(C.F64Mul)
+	OpF64Div                    = Opcode(C.F64Div)
+	OpF64Min                    = Opcode(C.F64Min)
+	OpF64Max                    = Opcode(C.F64Max)
+	OpF64Copysign               = Opcode(C.F64Copysign)
+	OpI32WrapI64                = Opcode(C.I32WrapI64)
+	OpI32TruncF32S              = Opcode(C.I32TruncF32S)
+	OpI32TruncF32U              = Opcode(C.I32TruncF32U)
+	OpI32TruncF64S              = Opcode(C.I32TruncF64S)
+	OpI32TruncF64U              = Opcode(C.I32TruncF64U)
+	OpI64ExtendI32S             = Opcode(C.I64ExtendI32S)
+	OpI64ExtendI32U             = Opcode(C.I64ExtendI32U)
+	OpI64TruncF32S              = Opcode(C.I64TruncF32S)
+	OpI64TruncF32U              = Opcode(C.I64TruncF32U)
+	OpI64TruncF64S              = Opcode(C.I64TruncF64S)
+	OpI64TruncF64U              = Opcode(C.I64TruncF64U)
+	OpF32ConvertI32S            = Opcode(C.F32ConvertI32S)
+	OpF32ConvertI32U            = Opcode(C.F32ConvertI32U)
+	OpF32ConvertI64S            = Opcode(C.F32ConvertI64S)
+	OpF32ConvertI64U            = Opcode(C.F32ConvertI64U)
+	OpF32DemoteF64              = Opcode(C.F32DemoteF64)
+	OpF64ConvertI32S            = Opcode(C.F64ConvertI32S)
+	OpF64ConvertI32U            = Opcode(C.F64ConvertI32U)
+	OpF64ConvertI64S            = Opcode(C.F64ConvertI64S)
+	OpF64ConvertI64U            = Opcode(C.F64ConvertI64U)
+	OpF64PromoteF32             = Opcode(C.F64PromoteF32)
+	OpI32ReinterpretF32         = Opcode(C.I32ReinterpretF32)
+	OpI64ReinterpretF64         = Opcode(C.I64ReinterpretF64)
+	OpF32ReinterpretI32         = Opcode(C.F32ReinterpretI32)
+	OpF64ReinterpretI64         = Opcode(C.F64ReinterpretI64)
+	OpI32Extend8S               = Opcode(C.I32Extend8S)
+	OpI32Extend16S              = Opcode(C.I32Extend16S)
+	OpI64Extend8S               = Opcode(C.I64Extend8S)
+	OpI64Extend16S              = Opcode(C.I64Extend16S)
+	OpI64Extend32S              = Opcode(C.I64Extend32S)
+	OpI32TruncSatF32S           = Opcode(C.I32TruncSatF32S)
+	OpI32TruncSatF32U           = Opcode(C.I32TruncSatF32U)
+	OpI32TruncSatF64S           = Opcode(C.I32TruncSatF64S)
+	OpI32TruncSatF64U           = Opcode(C.I32TruncSatF64U)
+	OpI64TruncSatF32S           = Opcode(C.I64TruncSatF32S)
+	OpI64TruncSatF32U           = Opcode(C.I64TruncSatF32U)
+	OpI64TruncSatF64S           = Opcode(C.I64TruncSatF64S)
+	OpI64TruncSatF64U           = Opcode(C.I64TruncSatF64U)
+	OpMemoryInit                = Opcode(C.MemoryInit)
+	OpDataDrop                  = Opcode(C.DataDrop)
+	OpMemoryCopy                = Opcode(C.MemoryCopy)
+	OpMemoryFill                = Opcode(C.MemoryFill)
+	OpTableInit                 = Opcode(C.TableInit)
+	OpElemDrop                  = Opcode(C.ElemDrop)
+	OpTableCopy                 = Opcode(C.TableCopy)
+	OpTableFill                 = Opcode(C.TableFill)
+	OpTableGet                  = Opcode(C.TableGet)
+	OpTableSet                  = Opcode(C.TableSet)
+	OpTableGrow                 = Opcode(C.TableGrow)
+	OpTableSize                 = Opcode(C.TableSize)
+	OpMemoryAtomicNotify        = Opcode(C.MemoryAtomicNotify)
+	OpMemoryAtomicWait32        = Opcode(C.MemoryAtomicWait32)
+	OpMemoryAtomicWait64        = Opcode(C.MemoryAtomicWait64)
+	OpAtomicFence               = Opcode(C.AtomicFence)
+	OpI32AtomicLoad             = Opcode(C.I32AtomicLoad)
+	OpI64AtomicLoad             = Opcode(C.I64AtomicLoad)
+	OpI32AtomicLoad8U           = Opcode(C.I32AtomicLoad8U)
+	OpI32AtomicLoad16U          = Opcode(C.I32AtomicLoad16U)
+	OpI64AtomicLoad8U           = Opcode(C.I64AtomicLoad8U)
+	OpI64AtomicLoad16U          = Opcode(C.I64AtomicLoad16U)
+	OpI64AtomicLoad32U          = Opcode(C.I64AtomicLoad32U)
+	OpI32AtomicStore            = Opcode(C.I32AtomicStore)
+	OpI64AtomicStore            = Opcode(C.I64AtomicStore)
+	OpI32AtomicStore8           = Opcode(C.I32AtomicStore8)
+	OpI32AtomicStore16          = Opcode(C.I32AtomicStore16)
+	OpI64AtomicStore8           = Opcode(C.I64AtomicStore8)
+	OpI64AtomicStore16          = Opcode(C.I64AtomicStore16)
+	OpI64AtomicStore32          = Opcode(C.I64AtomicStore32)
+	OpI32AtomicRmwAdd           = Opcode(C.I32AtomicRmwAdd)
+	OpI64AtomicRmwAdd           = Opcode(C.I64AtomicRmwAdd)
+	OpI32AtomicRmw8AddU         = Opcode(C.I32AtomicRmw8AddU)
+	OpI32AtomicRmw16AddU        = Opcode(C.I32AtomicRmw16AddU)
+	OpI64AtomicRmw8AddU         = Opcode(C.I64AtomicRmw8AddU)
+	OpI64AtomicRmw16AddU        = Opcode(C.I64AtomicRmw16AddU)
+	OpI64AtomicRmw32AddU        = Opcode(C.I64AtomicRmw32AddU)
+	OpI32AtomicRmwSub           = Opcode(C.I32AtomicRmwSub)
+	OpI64AtomicRmwSub           = Opcode(C.I64AtomicRmwSub)
+	OpI32AtomicRmw8SubU         = Opcode(C.I32AtomicRmw8SubU)
+	OpI32AtomicRmw16SubU        = Opcode(C.I32AtomicRmw16SubU)
+	OpI64AtomicRmw8SubU         = Opcode(C.I64AtomicRmw8SubU)
+	OpI64AtomicRmw16SubU        = Opcode(C.I64AtomicRmw16SubU)
+	OpI64AtomicRmw32SubU        = Opcode(C.I64AtomicRmw32SubU)
+	OpI32AtomicRmwAnd           = Opcode(C.I32AtomicRmwAnd)
+	OpI64AtomicRmwAnd           = Opcode(C.I64AtomicRmwAnd)
+	OpI32AtomicRmw8AndU         = Opcode(C.I32AtomicRmw8AndU)
+	OpI32AtomicRmw16AndU        = Opcode(C.I32AtomicRmw16AndU)
+	OpI64AtomicRmw8AndU         = Opcode(C.I64AtomicRmw8AndU)
+	OpI64AtomicRmw16AndU        = Opcode(C.I64AtomicRmw16AndU)
+	OpI64AtomicRmw32AndU        = Opcode(C.I64AtomicRmw32AndU)
+	OpI32AtomicRmwOr            = Opcode(C.I32AtomicRmwOr)
+	OpI64AtomicRmwOr            = Opcode(C.I64AtomicRmwOr)
+	OpI32AtomicRmw8OrU          = Opcode(C.I32AtomicRmw8OrU)
+	OpI32AtomicRmw16OrU         = Opcode(C.I32AtomicRmw16OrU)
+	OpI64AtomicRmw8OrU          = Opcode(C.I64AtomicRmw8OrU)
+	OpI64AtomicRmw16OrU         = Opcode(C.I64AtomicRmw16OrU)
+	OpI64AtomicRmw32OrU         = Opcode(C.I64AtomicRmw32OrU)
+	OpI32AtomicRmwXor           = Opcode(C.I32AtomicRmwXor)
+	OpI64AtomicRmwXor           = Opcode(C.I64AtomicRmwXor)
+	OpI32AtomicRmw8XorU         = Opcode(C.I32AtomicRmw8XorU)
+	OpI32AtomicRmw16XorU        = Opcode(C.I32AtomicRmw16XorU)
+	OpI64AtomicRmw8XorU         = Opcode(C.I64AtomicRmw8XorU)
+	OpI64AtomicRmw16XorU        = Opcode(C.I64AtomicRmw16XorU)
+	OpI64AtomicRmw32XorU        = Opcode(C.I64AtomicRmw32XorU)
+	OpI32AtomicRmwXchg          = Opcode(C.I32AtomicRmwXchg)
+	OpI64AtomicRmwXchg          = Opcode(C.I64AtomicRmwXchg)
+	OpI32AtomicRmw8XchgU        = Opcode(C.I32AtomicRmw8XchgU)
+	OpI32AtomicRmw16XchgU       = Opcode(C.I32AtomicRmw16XchgU)
+	OpI64AtomicRmw8XchgU        = Opcode(C.I64AtomicRmw8XchgU)
+	OpI64AtomicRmw16XchgU       = Opcode(C.I64AtomicRmw16XchgU)
+	OpI64AtomicRmw32XchgU       = Opcode(C.I64AtomicRmw32XchgU)
+	OpI32AtomicRmwCmpxchg       = Opcode(C.I32AtomicRmwCmpxchg)
+	OpI64AtomicRmwCmpxchg       = Opcode(C.I64AtomicRmwCmpxchg)
+	OpI32AtomicRmw8CmpxchgU     = Opcode(C.I32AtomicRmw8CmpxchgU)
+	OpI32AtomicRmw16CmpxchgU    = Opcode(C.I32AtomicRmw16CmpxchgU)
+	OpI64AtomicRmw8CmpxchgU     = Opcode(C.I64AtomicRmw8CmpxchgU)
+	OpI64AtomicRmw16CmpxchgU    = Opcode(C.I64AtomicRmw16CmpxchgU)
+	OpI64AtomicRmw32CmpxchgU    = Opcode(C.I64AtomicRmw32CmpxchgU)
+	OpV128Load                  = Opcode(C.V128Load)
+	OpV128Store                 = Opcode(C.V128Store)
+	OpV128Const                 = Opcode(C.V128Const)
+	OpI8x16Splat                = Opcode(C.I8x16Splat)
+	OpI8x16ExtractLaneS         = Opcode(C.I8x16ExtractLaneS)
+	OpI8x16ExtractLaneU         = Opcode(C.I8x16ExtractLaneU)
+	OpI8x16ReplaceLane          = Opcode(C.I8x16ReplaceLane)
+	OpI16x8Splat                = Opcode(C.I16x8Splat)
+	OpI16x8ExtractLaneS         = Opcode(C.I16x8ExtractLaneS)
+	OpI16x8ExtractLaneU         = Opcode(C.I16x8ExtractLaneU)
+	OpI16x8ReplaceLane          = Opcode(C.I16x8ReplaceLane)
+	OpI32x4Splat                = Opcode(C.I32x4Splat)
+	OpI32x4ExtractLane          = Opcode(C.I32x4ExtractLane)
+	OpI32x4ReplaceLane          = Opcode(C.I32x4ReplaceLane)
+	OpI64x2Splat                = Opcode(C.I64x2Splat)
+	OpI64x2ExtractLane          = Opcode(C.I64x2ExtractLane)
+	OpI64x2ReplaceLane          = Opcode(C.I64x2ReplaceLane)
+	OpF32x4Splat                = Opcode(C.F32x4Splat)
+	OpF32x4ExtractLane          = Opcode(C.F32x4ExtractLane)
+	OpF32x4ReplaceLane          = Opcode(C.F32x4ReplaceLane)
+	OpF64x2Splat                = Opcode(C.F64x2Splat)
+	OpF64x2ExtractLane          = Opcode(C.F64x2ExtractLane)
+	OpF64x2ReplaceLane          = Opcode(C.F64x2ReplaceLane)
+	OpI8x16Eq                   = Opcode(C.I8x16Eq)
+	OpI8x16Ne                   = Opcode(C.I8x16Ne)
+	OpI8x16LtS                  = Opcode(C.I8x16LtS)
+	OpI8x16LtU                  = Opcode(C.I8x16LtU)
+	OpI8x16GtS                  = Opcode(C.I8x16GtS)
+	OpI8x16GtU                  = Opcode(C.I8x16GtU)
+	OpI8x16LeS                  = Opcode(C.I8x16LeS)
+	OpI8x16LeU                  = Opcode(C.I8x16LeU)
+	OpI8x16GeS                  = Opcode(C.I8x16GeS)
+	OpI8x16GeU                  = Opcode(C.I8x16GeU)
+	OpI16x8Eq                   = Opcode(C.I16x8Eq)
+	OpI16x8Ne                   = Opcode(C.I16x8Ne)
+	OpI16x8LtS                  = Opcode(C.I16x8LtS)
+	OpI16x8LtU                  = Opcode(C.I16x8LtU)
+	OpI16x8GtS                  = Opcode(C.I16x8GtS)
+	OpI16x8GtU                  = Opcode(C.I16x8GtU)
+	OpI16x8LeS                  = Opcode(C.I16x8LeS)
+	OpI16x8LeU                  = Opcode(C.I16x8LeU)
+	OpI16x8GeS                  = Opcode(C.I16x8GeS)
+	OpI16x8GeU                  = Opcode(C.I16x8GeU)
+	OpI32x4Eq                   = Opcode(C.I32x4Eq)
+	OpI32x4Ne                   = Opcode(C.I32x4Ne)
+	OpI32x4LtS                  = Opcode(C.I32x4LtS)
+	OpI32x4LtU                  = Opcode(C.I32x4LtU)
+	OpI32x4GtS                  = Opcode(C.I32x4GtS)
+	OpI32x4GtU                  = Opcode(C.I32x4GtU)
+	OpI32x4LeS                  = Opcode(C.I32x4LeS)
+	OpI32x4LeU                  = Opcode(C.I32x4LeU)
+	OpI32x4GeS                  = Opcode(C.I32x4GeS)
+	OpI32x4GeU                  = Opcode(C.I32x4GeU)
+	OpI64x2Eq                   = Opcode(C.I64x2Eq)
+	OpI64x2Ne                   = Opcode(C.I64x2Ne)
+	OpI64x2LtS                  = Opcode(C.I64x2LtS)
+	OpI64x2GtS                  = Opcode(C.I64x2GtS)
+	OpI64x2LeS                  = Opcode(C.I64x2LeS)
+	OpI64x2GeS                  = Opcode(C.I64x2GeS)
+	OpF32x4Eq                   = Opcode(C.F32x4Eq)
+	OpF32x4Ne                   = Opcode(C.F32x4Ne)
+	OpF32x4Lt                   = Opcode(C.F32x4Lt)
+	OpF32x4Gt                   = Opcode(C.F32x4Gt)
+	OpF32x4Le                   = Opcode(C.F32x4Le)
+	OpF32x4Ge                   = Opcode(C.F32x4Ge)
+	OpF64x2Eq                   = Opcode(C.F64x2Eq)
+	OpF64x2Ne                   = Opcode(C.F64x2Ne)
+	OpF64x2Lt                   = Opcode(C.F64x2Lt)
+	OpF64x2Gt                   = Opcode(C.F64x2Gt)
+	OpF64x2Le                   = Opcode(C.F64x2Le)
+	OpF64x2Ge                   = Opcode(C.F64x2Ge)
+	OpV128Not                   = Opcode(C.V128Not)
+	OpV128And                   = Opcode(C.V128And)
+	OpV128AndNot                = Opcode(C.V128AndNot)
+	OpV128Or                    = Opcode(C.V128Or)
+	OpV128Xor                   = Opcode(C.V128Xor)
+	OpV128Bitselect             = Opcode(C.V128Bitselect)
+	OpV128AnyTrue               = Opcode(C.V128AnyTrue)
+	OpI8x16Abs                  = Opcode(C.I8x16Abs)
+	OpI8x16Neg                  = Opcode(C.I8x16Neg)
+	OpI8x16AllTrue              = Opcode(C.I8x16AllTrue)
+	OpI8x16Bitmask              = Opcode(C.I8x16Bitmask)
+	OpI8x16Shl                  = Opcode(C.I8x16Shl)
+	OpI8x16ShrS                 = Opcode(C.I8x16ShrS)
+	OpI8x16ShrU                 = Opcode(C.I8x16ShrU)
+	OpI8x16Add                  = Opcode(C.I8x16Add)
+	OpI8x16AddSatS              = Opcode(C.I8x16AddSatS)
+	OpI8x16AddSatU              = Opcode(C.I8x16AddSatU)
+	OpI8x16Sub                  = Opcode(C.I8x16Sub)
+	OpI8x16SubSatS              = Opcode(C.I8x16SubSatS)
+	OpI8x16SubSatU              = Opcode(C.I8x16SubSatU)
+	OpI8x16MinS                 = Opcode(C.I8x16MinS)
+	OpI8x16MinU                 = Opcode(C.I8x16MinU)
+	OpI8x16MaxS                 = Opcode(C.I8x16MaxS)
+	OpI8x16MaxU                 = Opcode(C.I8x16MaxU)
+	OpI8x16Popcnt               = Opcode(C.I8x16Popcnt)
+	OpI16x8Abs                  = Opcode(C.I16x8Abs)
+	OpI16x8Neg                  = Opcode(C.I16x8Neg)
+	OpI16x8AllTrue              = Opcode(C.I16x8AllTrue)
+	OpI16x8Bitmask              = Opcode(C.I16x8Bitmask)
+	OpI16x8Shl                  = Opcode(C.I16x8Shl)
+	OpI16x8ShrS                 = Opcode(C.I16x8ShrS)
+	OpI16x8ShrU                 = Opcode(C.I16x8ShrU)
+	OpI16x8Add                  = Opcode(C.I16x8Add)
+	OpI16x8AddSatS              = Opcode(C.I16x8AddSatS)
+	OpI16x8AddSatU              = Opcode(C.I16x8AddSatU)
+	OpI16x8Sub                  = Opcode(C.I16x8Sub)
+	OpI16x8SubSatS              = Opcode(C.I16x8SubSatS)
+	OpI16x8SubSatU              = Opcode(C.I16x8SubSatU)
+	OpI16x8Mul                  = Opcode(C.I16x8Mul)
+	OpI16x8MinS                 = Opcode(C.I16x8MinS)
+	OpI16x8MinU                 = Opcode(C.I16x8MinU)
+	OpI16x8MaxS                 = Opcode(C.I16x8MaxS)
+	OpI16x8MaxU                 = Opcode(C.I16x8MaxU)
+	OpI16x8ExtAddPairwiseI8x16S = Opcode(C.I16x8ExtAddPairwiseI8x16S)
+	OpI16x8ExtAddPairwiseI8x16U = Opcode(C.I16x8ExtAddPairwiseI8x16U)
+	OpI32x4Abs                  = Opcode(C.I32x4Abs)
+	OpI32x4Neg                  = Opcode(C.I32x4Neg)
+	OpI32x4AllTrue              = Opcode(C.I32x4AllTrue)
+	OpI32x4Bitmask              = Opcode(C.I32x4Bitmask)
+	OpI32x4Shl                  = Opcode(C.I32x4Shl)
+	OpI32x4ShrS                 = Opcode(C.I32x4ShrS)
+	OpI32x4ShrU                 = Opcode(C.I32x4ShrU)
+	OpI32x4Add                  = Opcode(C.I32x4Add)
+	OpI32x4Sub                  = Opcode(C.I32x4Sub)
+	OpI32x4Mul                  = Opcode(C.I32x4Mul)
+	OpI32x4MinS                 = Opcode(C.I32x4MinS)
+	OpI32x4MinU                 = Opcode(C.I32x4MinU)
+	OpI32x4MaxS                 = Opcode(C.I32x4MaxS)
+	OpI32x4MaxU                 = Opcode(C.I32x4MaxU)
+	OpI32x4DotI16x8S            = Opcode(C.I32x4DotI16x8S)
+	OpI32x4ExtAddPairwiseI16x8S = Opcode(C.I32x4ExtAddPairwiseI16x8S)
+	OpI32x4ExtAddPairwiseI16x8U = Opcode(C.I32x4ExtAddPairwiseI16x8U)
+	OpI64x2Abs                  = Opcode(C.I64x2Abs)
+	OpI64x2Neg                  = Opcode(C.I64x2Neg)
+	OpI64x2AllTrue              = Opcode(C.I64x2AllTrue)
+	OpI64x2Bitmask              = Opcode(C.I64x2Bitmask)
+	OpI64x2Shl                  = Opcode(C.I64x2Shl)
+	OpI64x2ShrS                 = Opcode(C.I64x2ShrS)
+	OpI64x2ShrU                 = Opcode(C.I64x2ShrU)
+	OpI64x2Add                  = Opcode(C.I64x2Add)
+	OpI64x2Sub                  = Opcode(C.I64x2Sub)
+	OpI64x2Mul                  = Opcode(C.I64x2Mul)
+	OpF32x4Ceil                 = Opcode(C.F32x4Ceil)
+	OpF32x4Floor                = Opcode(C.F32x4Floor)
+	OpF32x4Trunc                = Opcode(C.F32x4Trunc)
+	OpF32x4Nearest              = Opcode(C.F32x4Nearest)
+	OpF64x2Ceil                 = Opcode(C.F64x2Ceil)
+	OpF64x2Floor                = Opcode(C.F64x2Floor)
+	OpF64x2Trunc                = Opcode(C.F64x2Trunc)
+	OpF64x2Nearest              = Opcode(C.F64x2Nearest)
+	OpF32x4Abs                  = Opcode(C.F32x4Abs)
+	OpF32x4Neg                  = Opcode(C.F32x4Neg)
+	OpF32x4Sqrt                 = Opcode(C.F32x4Sqrt)
+	OpF32x4Add                  = Opcode(C.F32x4Add)
+	OpF32x4Sub                  = Opcode(C.F32x4Sub)
+	OpF32x4Mul                  = Opcode(C.F32x4Mul)
+	OpF32x4Div                  = Opcode(C.F32x4Div)
+	OpF32x4Min                  = Opcode(C.F32x4Min)
+	OpF32x4Max                  = Opcode(C.F32x4Max)
+	OpF32x4PMin                 = Opcode(C.F32x4PMin)
+	OpF32x4PMax                 = Opcode(C.F32x4PMax)
+	OpF64x2Abs                  = Opcode(C.F64x2Abs)
+	OpF64x2Neg                  = Opcode(C.F64x2Neg)
+	OpF64x2Sqrt                 = Opcode(C.F64x2Sqrt)
+	OpF64x2Add                  = Opcode(C.F64x2Add)
+	OpF64x2Sub                  = Opcode(C.F64x2Sub)
+	OpF64x2Mul                  = Opcode(C.F64x2Mul)
+	OpF64x2Div                  = Opcode(C.F64x2Div)
+	OpF64x2Min                  = Opcode(C.F64x2Min)
+	OpF64x2Max                  = Opcode(C.F64x2Max)
+	OpF64x2PMin                 = Opcode(C.F64x2PMin)
+	OpF64x2PMax                 = Opcode(C.F64x2PMax)
+	OpI32x4TruncSatF32x4S       = Opcode(C.I32x4TruncSatF32x4S)
+	OpI32x4TruncSatF32x4U       = Opcode(C.I32x4TruncSatF32x4U)
+	OpF32x4ConvertI32x4S        = Opcode(C.F32x4ConvertI32x4S)
+	OpF32x4ConvertI32x4U        = Opcode(C.F32x4ConvertI32x4U)
+	OpI8x16Swizzle              = Opcode(C.I8x16Swizzle)
+	OpI8x16Shuffle              = Opcode(C.I8x16Shuffle)
+	OpV128Load8Splat            = Opcode(C.V128Load8Splat)
+	OpV128Load16Splat           = Opcode(C.V128Load16Splat)
+	OpV128Load32Splat           = Opcode(C.V128Load32Splat)
+	OpV128Load32Zero            = Opcode(C.V128Load32Zero)
+	OpV128Load64Splat           = Opcode(C.V128Load64Splat)
+	OpV128Load64Zero            = Opcode(C.V128Load64Zero)
+	OpI8x16NarrowI16x8S         = Opcode(C.I8x16NarrowI16x8S)
+	OpI8x16NarrowI16x8U         = Opcode(C.I8x16NarrowI16x8U)
+	OpI16x8NarrowI32x4S         = Opcode(C.I16x8NarrowI32x4S)
+	OpI16x8NarrowI32x4U         = Opcode(C.I16x8NarrowI32x4U)
+	OpI16x8ExtendLowI8x16S      = Opcode(C.I16x8ExtendLowI8x16S)
+	OpI16x8ExtendHighI8x16S     = Opcode(C.I16x8ExtendHighI8x16S)
+	OpI16x8ExtendLowI8x16U      = Opcode(C.I16x8ExtendLowI8x16U)
+	OpI16x8ExtendHighI8x16U     = Opcode(C.I16x8ExtendHighI8x16U)
+	OpI32x4ExtendLowI16x8S      = Opcode(C.I32x4ExtendLowI16x8S)
+	OpI32x4ExtendHighI16x8S     = Opcode(C.I32x4ExtendHighI16x8S)
+	OpI32x4ExtendLowI16x8U      = Opcode(C.I32x4ExtendLowI16x8U)
+	OpI32x4ExtendHighI16x8U     = Opcode(C.I32x4ExtendHighI16x8U)
+	OpI64x2ExtendLowI32x4S      = Opcode(C.I64x2ExtendLowI32x4S)
+	OpI64x2ExtendHighI32x4S     = Opcode(C.I64x2ExtendHighI32x4S)
+	OpI64x2ExtendLowI32x4U      = Opcode(C.I64x2ExtendLowI32x4U)
+	OpI64x2ExtendHighI32x4U     = Opcode(C.I64x2ExtendHighI32x4U)
+	OpI16x8ExtMulLowI8x16S      = Opcode(C.I16x8ExtMulLowI8x16S)
+	OpI16x8ExtMulHighI8x16S     = Opcode(C.I16x8ExtMulHighI8x16S)
+	OpI16x8ExtMulLowI8x16U      = Opcode(C.I16x8ExtMulLowI8x16U)
+	OpI16x8ExtMulHighI8x16U     = Opcode(C.I16x8ExtMulHighI8x16U)
+	OpI32x4ExtMulLowI16x8S      = Opcode(C.I32x4ExtMulLowI16x8S)
+	OpI32x4ExtMulHighI16x8S     = Opcode(C.I32x4ExtMulHighI16x8S)
+	OpI32x4ExtMulLowI16x8U      = Opcode(C.I32x4ExtMulLowI16x8U)
+	OpI32x4ExtMulHighI16x8U     = Opcode(C.I32x4ExtMulHighI16x8U)
+	OpI64x2ExtMulLowI32x4S      = Opcode(C.I64x2ExtMulLowI32x4S)
+	OpI64x2ExtMulHighI32x4S     = Opcode(C.I64x2ExtMulHighI32x4S)
+	OpI64x2ExtMulLowI32x4U      = Opcode(C.I64x2ExtMulLowI32x4U)
+	OpI64x2ExtMulHighI32x4U     = Opcode(C.I64x2ExtMulHighI32x4U)
+	OpV128Load8x8S              = Opcode(C.V128Load8x8S)
+	OpV128Load8x8U              = Opcode(C.V128Load8x8U)
+	OpV128Load16x4S             = Opcode(C.V128Load16x4S)
+	OpV128Load16x4U             = Opcode(C.V128Load16x4U)
+	OpV128Load32x2S             = Opcode(C.V128Load32x2S)
+	OpV128Load32x2U             = Opcode(C.V128Load32x2U)
+	OpV128Load8Lane             = Opcode(C.V128Load8Lane)
+	OpV128Load16Lane            = Opcode(C.V128Load16Lane)
+	OpV128Load32Lane            = Opcode(C.V128Load32Lane)
+	OpV128Load64Lane            = Opcode(C.V128Load64Lane)
+	OpV128Store8Lane            = Opcode(C.V128Store8Lane)
+	OpV128Store16Lane           = Opcode(C.V128Store16Lane)
+	OpV128Store32Lane           = Opcode(C.V128Store32Lane)
+	OpV128Store64Lane           = Opcode(C.V128Store64Lane)
+	OpI8x16RoundingAverageU     = Opcode(C.I8x16RoundingAverageU)
+	OpI16x8RoundingAverageU     = Opcode(C.I16x8RoundingAverageU)
+	OpI16x8Q15MulrSatS          = Opcode(C.I16x8Q15MulrSatS)
+	OpF32x4DemoteF64x2Zero      = Opcode(C.F32x4DemoteF64x2Zero)
+	OpF64x2PromoteLowF32x4      = Opcode(C.F64x2PromoteLowF32x4)
+	OpF64x2ConvertLowI32x4S     = Opcode(C.F64x2ConvertLowI32x4S)
+	OpF64x2ConvertLowI32x4U     = Opcode(C.F64x2ConvertLowI32x4U)
+	OpI32x4TruncSatF64x2SZero   = Opcode(C.I32x4TruncSatF64x2SZero)
+	OpI32x4TruncSatF64x2UZero   = Opcode(C.I32x4TruncSatF64x2UZero)
+)
+
+// opcodeClasses maps every Opcode to its OperatorClass.
+var opcodeClasses = map[Opcode]OperatorClass{
+	OpUnreachable:               OperatorControl,
+	OpNop:                       OperatorControl,
+	OpBlock:                     OperatorControl,
+	OpLoop:                      OperatorControl,
+	OpIf:                        OperatorControl,
+	OpElse:                      OperatorControl,
+	OpTry:                       OperatorControl,
+	OpCatch:                     OperatorControl,
+	OpCatchAll:                  OperatorControl,
+	OpDelegate:                  OperatorControl,
+	OpThrow:                     OperatorControl,
+	OpRethrow:                   OperatorControl,
+	OpUnwind:                    OperatorControl,
+	OpEnd:                       OperatorControl,
+	OpBr:                        OperatorControl,
+	OpBrIf:                      OperatorControl,
+	OpBrTable:                   OperatorControl,
+	OpReturn:                    OperatorControl,
+	OpCall:                      OperatorCall,
+	OpCallIndirect:              OperatorCall,
+	OpReturnCall:                OperatorCall,
+	OpReturnCallIndirect:        OperatorCall,
+	OpDrop:                      OperatorVariable,
+	OpSelect:                    OperatorVariable,
+	OpTypedSelect:               OperatorVariable,
+	OpLocalGet:                  OperatorVariable,
+	OpLocalSet:                  OperatorVariable,
+	OpLocalTee:                  OperatorVariable,
+	OpGlobalGet:                 OperatorVariable,
+	OpGlobalSet:                 OperatorVariable,
+	OpI32Load:                   OperatorLoad,
+	OpI64Load:                   OperatorLoad,
+	OpF32Load:                   OperatorLoad,
+	OpF64Load:                   OperatorLoad,
+	OpI32Load8S:                 OperatorLoad,
+	OpI32Load8U:                 OperatorLoad,
+	OpI32Load16S:                OperatorLoad,
+	OpI32Load16U:                OperatorLoad,
+	OpI64Load8S:                 OperatorLoad,
+	OpI64Load8U:                 OperatorLoad,
+	OpI64Load16S:                OperatorLoad,
+	OpI64Load16U:                OperatorLoad,
+	OpI64Load32S:                OperatorLoad,
+	OpI64Load32U:                OperatorLoad,
+	OpI32Store:                  OperatorStore,
+	OpI64Store:                  OperatorStore,
+	OpF32Store:                  OperatorStore,
+	OpF64Store:                  OperatorStore,
+	OpI32Store8:                 OperatorStore,
+	OpI32Store16:                OperatorStore,
+	OpI64Store8:                 OperatorStore,
+	OpI64Store16:                OperatorStore,
+	OpI64Store32:                OperatorStore,
+	OpMemorySize:                OperatorMemory,
+	OpMemoryGrow:                OperatorMemory,
+	OpI32Const:                  OperatorInteger,
+	OpI64Const:                  OperatorInteger,
+	OpF32Const:                  OperatorFloat,
+	OpF64Const:                  OperatorFloat,
+	OpRefNull:                   OperatorReference,
+	OpRefIsNull:                 OperatorReference,
+	OpRefFunc:                   OperatorReference,
+	OpI32Eqz:                    OperatorInteger,
+	OpI32Eq:                     OperatorInteger,
+	OpI32Ne:                     OperatorInteger,
+	OpI32LtS:                    OperatorInteger,
+	OpI32LtU:                    OperatorInteger,
+	OpI32GtS:                    OperatorInteger,
+	OpI32GtU:                    OperatorInteger,
+	OpI32LeS:                    OperatorInteger,
+	OpI32LeU:                    OperatorInteger,
+	OpI32GeS:                    OperatorInteger,
+	OpI32GeU:                    OperatorInteger,
+	OpI64Eqz:                    OperatorInteger,
+	OpI64Eq:                     OperatorInteger,
+	OpI64Ne:                     OperatorInteger,
+	OpI64LtS:                    OperatorInteger,
+	OpI64LtU:                    OperatorInteger,
+	OpI64GtS:                    OperatorInteger,
+	OpI64GtU:                    OperatorInteger,
+	OpI64LeS:                    OperatorInteger,
+	OpI64LeU:                    OperatorInteger,
+	OpI64GeS:                    OperatorInteger,
+	OpI64GeU:                    OperatorInteger,
+	OpF32Eq:                     OperatorFloat,
+	OpF32Ne:                     OperatorFloat,
+	OpF32Lt:                     OperatorFloat,
+	OpF32Gt:                     OperatorFloat,
+	OpF32Le:                     OperatorFloat,
+	OpF32Ge:                     OperatorFloat,
+	OpF64Eq:                     OperatorFloat,
+	OpF64Ne:                     OperatorFloat,
+	OpF64Lt:                     OperatorFloat,
+	OpF64Gt:                     OperatorFloat,
+	OpF64Le:                     OperatorFloat,
+	OpF64Ge:                     OperatorFloat,
+	OpI32Clz:                    OperatorInteger,
+	OpI32Ctz:                    OperatorInteger,
+	OpI32Popcnt:                 OperatorInteger,
+	OpI32Add:                    OperatorInteger,
+	OpI32Sub:                    OperatorInteger,
+	OpI32Mul:                    OperatorInteger,
+	OpI32DivS:                   OperatorInteger,
+	OpI32DivU:                   OperatorInteger,
+	OpI32RemS:                   OperatorInteger,
+	OpI32RemU:                   OperatorInteger,
+	OpI32And:                    OperatorInteger,
+	OpI32Or:                     OperatorInteger,
+	OpI32Xor:                    OperatorInteger,
+	OpI32Shl:                    OperatorInteger,
+	OpI32ShrS:                   OperatorInteger,
+	OpI32ShrU:                   OperatorInteger,
+	OpI32Rotl:                   OperatorInteger,
+	OpI32Rotr:                   OperatorInteger,
+	OpI64Clz:                    OperatorInteger,
+	OpI64Ctz:                    OperatorInteger,
+	OpI64Popcnt:                 OperatorInteger,
+	OpI64Add:                    OperatorInteger,
+	OpI64Sub:                    OperatorInteger,
+	OpI64Mul:                    OperatorInteger,
+	OpI64DivS:                   OperatorInteger,
+	OpI64DivU:                   OperatorInteger,
+	OpI64RemS:                   OperatorInteger,
+	OpI64RemU:                   OperatorInteger,
+	OpI64And:                    OperatorInteger,
+	OpI64Or:                     OperatorInteger,
+	OpI64Xor:                    OperatorInteger,
+	OpI64Shl:                    OperatorInteger,
+	OpI64ShrS:                   OperatorInteger,
+	OpI64ShrU:                   OperatorInteger,
+	OpI64Rotl:                   OperatorInteger,
+	OpI64Rotr:                   OperatorInteger,
+	OpF32Abs:                    OperatorFloat,
+	OpF32Neg:                    OperatorFloat,
+	OpF32Ceil:                   OperatorFloat,
+	OpF32Floor:                  OperatorFloat,
+	OpF32Trunc:                  OperatorFloat,
+	OpF32Nearest:                OperatorFloat,
+	OpF32Sqrt:                   OperatorFloat,
+	OpF32Add:                    OperatorFloat,
+	OpF32Sub:                    OperatorFloat,
+	OpF32Mul:                    OperatorFloat,
+	OpF32Div:                    OperatorFloat,
+	OpF32Min:                    OperatorFloat,
+	OpF32Max:                    OperatorFloat,
+	OpF32Copysign:               OperatorFloat,
+	OpF64Abs:                    OperatorFloat,
+	OpF64Neg:                    OperatorFloat,
+	OpF64Ceil:                   OperatorFloat,
+	OpF64Floor:                  OperatorFloat,
+	OpF64Trunc:                  OperatorFloat,
+	OpF64Nearest:                OperatorFloat,
+	OpF64Sqrt:                   OperatorFloat,
+	OpF64Add:                    OperatorFloat,
+	OpF64Sub:                    OperatorFloat,
+	OpF64Mul:                    OperatorFloat,
+	OpF64Div:                    OperatorFloat,
+	OpF64Min:                    OperatorFloat,
+	OpF64Max:                    OperatorFloat,
+	OpF64Copysign:               OperatorFloat,
+	OpI32WrapI64:                OperatorInteger,
+	OpI32TruncF32S:              OperatorFloat,
+	OpI32TruncF32U:              OperatorFloat,
+	OpI32TruncF64S:              OperatorFloat,
+	OpI32TruncF64U:              OperatorFloat,
+	OpI64ExtendI32S:             OperatorInteger,
+	OpI64ExtendI32U:             OperatorInteger,
+	OpI64TruncF32S:              OperatorFloat,
+	OpI64TruncF32U:              OperatorFloat,
+	OpI64TruncF64S:              OperatorFloat,
+	OpI64TruncF64U:              OperatorFloat,
+	OpF32ConvertI32S:            OperatorFloat,
+	OpF32ConvertI32U:            OperatorFloat,
+	OpF32ConvertI64S:            OperatorFloat,
+	OpF32ConvertI64U:            OperatorFloat,
+	OpF32DemoteF64:              OperatorFloat,
+	OpF64ConvertI32S:            OperatorFloat,
+	OpF64ConvertI32U:            OperatorFloat,
+	OpF64ConvertI64S:            OperatorFloat,
+	OpF64ConvertI64U:            OperatorFloat,
+	OpF64PromoteF32:             OperatorFloat,
+	OpI32ReinterpretF32:         OperatorFloat,
+	OpI64ReinterpretF64:         OperatorFloat,
+	OpF32ReinterpretI32:         OperatorFloat,
+	OpF64ReinterpretI64:         OperatorFloat,
+	OpI32Extend8S:               OperatorInteger,
+	OpI32Extend16S:              OperatorInteger,
+	OpI64Extend8S:               OperatorInteger,
+	OpI64Extend16S:              OperatorInteger,
+	OpI64Extend32S:              OperatorInteger,
+	OpI32TruncSatF32S:           OperatorFloat,
+	OpI32TruncSatF32U:           OperatorFloat,
+	OpI32TruncSatF64S:           OperatorFloat,
+	OpI32TruncSatF64U:           OperatorFloat,
+	OpI64TruncSatF32S:           OperatorFloat,
+	OpI64TruncSatF32U:           OperatorFloat,
+	OpI64TruncSatF64S:           OperatorFloat,
+	OpI64TruncSatF64U:           OperatorFloat,
+	OpMemoryInit:                OperatorMemory,
+	OpDataDrop:                  OperatorMemory,
+	OpMemoryCopy:                OperatorMemory,
+	OpMemoryFill:                OperatorMemory,
+	OpTableInit:                 OperatorMemory,
+	OpElemDrop:                  OperatorMemory,
+	OpTableCopy:                 OperatorMemory,
+	OpTableFill:                 OperatorMemory,
+	OpTableGet:                  OperatorMemory,
+	OpTableSet:                  OperatorMemory,
+	OpTableGrow:                 OperatorMemory,
+	OpTableSize:                 OperatorMemory,
+	OpMemoryAtomicNotify:        OperatorAtomic,
+	OpMemoryAtomicWait32:        OperatorAtomic,
+	OpMemoryAtomicWait64:        OperatorAtomic,
+	OpAtomicFence:               OperatorAtomic,
+	OpI32AtomicLoad:             OperatorAtomic,
+	OpI64AtomicLoad:             OperatorAtomic,
+	OpI32AtomicLoad8U:           OperatorAtomic,
+	OpI32AtomicLoad16U:          OperatorAtomic,
+	OpI64AtomicLoad8U:           OperatorAtomic,
+	OpI64AtomicLoad16U:          OperatorAtomic,
+	OpI64AtomicLoad32U:          OperatorAtomic,
+	OpI32AtomicStore:            OperatorAtomic,
+	OpI64AtomicStore:            OperatorAtomic,
+	OpI32AtomicStore8:           OperatorAtomic,
+	OpI32AtomicStore16:          OperatorAtomic,
+	OpI64AtomicStore8:           OperatorAtomic,
+	OpI64AtomicStore16:          OperatorAtomic,
+	OpI64AtomicStore32:          OperatorAtomic,
+	OpI32AtomicRmwAdd:           OperatorAtomic,
+	OpI64AtomicRmwAdd:           OperatorAtomic,
+	OpI32AtomicRmw8AddU:         OperatorAtomic,
+	OpI32AtomicRmw16AddU:        OperatorAtomic,
+	OpI64AtomicRmw8AddU:         OperatorAtomic,
+	OpI64AtomicRmw16AddU:        OperatorAtomic,
+	OpI64AtomicRmw32AddU:        OperatorAtomic,
+	OpI32AtomicRmwSub:           OperatorAtomic,
+	OpI64AtomicRmwSub:           OperatorAtomic,
+	OpI32AtomicRmw8SubU:         OperatorAtomic,
+	OpI32AtomicRmw16SubU:        OperatorAtomic,
+	OpI64AtomicRmw8SubU:         OperatorAtomic,
+	OpI64AtomicRmw16SubU:        OperatorAtomic,
+	OpI64AtomicRmw32SubU:        OperatorAtomic,
+	OpI32AtomicRmwAnd:           OperatorAtomic,
+	OpI64AtomicRmwAnd:           OperatorAtomic,
+	OpI32AtomicRmw8AndU:         OperatorAtomic,
+	OpI32AtomicRmw16AndU:        OperatorAtomic,
+	OpI64AtomicRmw8AndU:         OperatorAtomic,
+	OpI64AtomicRmw16AndU:        OperatorAtomic,
+	OpI64AtomicRmw32AndU:        OperatorAtomic,
+	OpI32AtomicRmwOr:            OperatorAtomic,
+	OpI64AtomicRmwOr:            OperatorAtomic,
+	OpI32AtomicRmw8OrU:          OperatorAtomic,
+	OpI32AtomicRmw16OrU:         OperatorAtomic,
+	OpI64AtomicRmw8OrU:          OperatorAtomic,
+	OpI64AtomicRmw16OrU:         OperatorAtomic,
+	OpI64AtomicRmw32OrU:         OperatorAtomic,
+	OpI32AtomicRmwXor:           OperatorAtomic,
+	OpI64AtomicRmwXor:           OperatorAtomic,
+	OpI32AtomicRmw8XorU:         OperatorAtomic,
+	OpI32AtomicRmw16XorU:        OperatorAtomic,
+	OpI64AtomicRmw8XorU:         OperatorAtomic,
+	OpI64AtomicRmw16XorU:        OperatorAtomic,
+	OpI64AtomicRmw32XorU:        OperatorAtomic,
+	OpI32AtomicRmwXchg:          OperatorAtomic,
+	OpI64AtomicRmwXchg:          OperatorAtomic,
+	OpI32AtomicRmw8XchgU:        OperatorAtomic,
+	OpI32AtomicRmw16XchgU:       OperatorAtomic,
+	OpI64AtomicRmw8XchgU:        OperatorAtomic,
+	OpI64AtomicRmw16XchgU:       OperatorAtomic,
+	OpI64AtomicRmw32XchgU:       OperatorAtomic,
+	OpI32AtomicRmwCmpxchg:       OperatorAtomic,
+	OpI64AtomicRmwCmpxchg:       OperatorAtomic,
+	OpI32AtomicRmw8CmpxchgU:     OperatorAtomic,
+	OpI32AtomicRmw16CmpxchgU:    OperatorAtomic,
+	OpI64AtomicRmw8CmpxchgU:     OperatorAtomic,
+	OpI64AtomicRmw16CmpxchgU:    OperatorAtomic,
+	OpI64AtomicRmw32CmpxchgU:    OperatorAtomic,
+	OpV128Load:                  OperatorLoad,
+	OpV128Store:                 OperatorStore,
+	OpV128Const:                 OperatorVector,
+	OpI8x16Splat:                OperatorVector,
+	OpI8x16ExtractLaneS:         OperatorVector,
+	OpI8x16ExtractLaneU:         OperatorVector,
+	OpI8x16ReplaceLane:          OperatorVector,
+	OpI16x8Splat:                OperatorVector,
+	OpI16x8ExtractLaneS:         OperatorVector,
+	OpI16x8ExtractLaneU:         OperatorVector,
+	OpI16x8ReplaceLane:          OperatorVector,
+	OpI32x4Splat:                OperatorVector,
+	OpI32x4ExtractLane:          OperatorVector,
+	OpI32x4ReplaceLane:          OperatorVector,
+	OpI64x2Splat:                OperatorVector,
+	OpI64x2ExtractLane:          OperatorVector,
+	OpI64x2ReplaceLane:          OperatorVector,
+	OpF32x4Splat:                OperatorVector,
+	OpF32x4ExtractLane:          OperatorVector,
+	OpF32x4ReplaceLane:          OperatorVector,
+	OpF64x2Splat:                OperatorVector,
+	OpF64x2ExtractLane:          OperatorVector,
+	OpF64x2ReplaceLane:          OperatorVector,
+	OpI8x16Eq:                   OperatorVector,
+	OpI8x16Ne:                   OperatorVector,
+	OpI8x16LtS:                  OperatorVector,
+	OpI8x16LtU:                  OperatorVector,
+	OpI8x16GtS:                  OperatorVector,
+	OpI8x16GtU:                  OperatorVector,
+	OpI8x16LeS:                  OperatorVector,
+	OpI8x16LeU:                  OperatorVector,
+	OpI8x16GeS:                  OperatorVector,
+	OpI8x16GeU:                  OperatorVector,
+	OpI16x8Eq:                   OperatorVector,
+	OpI16x8Ne:                   OperatorVector,
+	OpI16x8LtS:                  OperatorVector,
+	OpI16x8LtU:                  OperatorVector,
+	OpI16x8GtS:                  OperatorVector,
+	OpI16x8GtU:                  OperatorVector,
+	OpI16x8LeS:                  OperatorVector,
+	OpI16x8LeU:                  OperatorVector,
+	OpI16x8GeS:                  OperatorVector,
+	OpI16x8GeU:                  OperatorVector,
+	OpI32x4Eq:                   OperatorVector,
+	OpI32x4Ne:                   OperatorVector,
+	OpI32x4LtS:                  OperatorVector,
+	OpI32x4LtU:                  OperatorVector,
+	OpI32x4GtS:                  OperatorVector,
+	OpI32x4GtU:                  OperatorVector,
+	OpI32x4LeS:                  OperatorVector,
+	OpI32x4LeU:                  OperatorVector,
+	OpI32x4GeS:                  OperatorVector,
+	OpI32x4GeU:                  OperatorVector,
+	OpI64x2Eq:                   OperatorVector,
+	OpI64x2Ne:                   OperatorVector,
+	OpI64x2LtS:                  OperatorVector,
+	OpI64x2GtS:                  OperatorVector,
+	OpI64x2LeS:                  OperatorVector,
+	OpI64x2GeS:                  OperatorVector,
+	OpF32x4Eq:                   OperatorVector,
+	OpF32x4Ne:                   OperatorVector,
+	OpF32x4Lt:                   OperatorVector,
+	OpF32x4Gt:                   OperatorVector,
+	OpF32x4Le:                   OperatorVector,
+	OpF32x4Ge:                   OperatorVector,
+	OpF64x2Eq:                   OperatorVector,
+	OpF64x2Ne:                   OperatorVector,
+	OpF64x2Lt:                   OperatorVector,
+	OpF64x2Gt:                   OperatorVector,
+	OpF64x2Le:                   OperatorVector,
+	OpF64x2Ge:                   OperatorVector,
+	OpV128Not:                   OperatorVector,
+	OpV128And:                   OperatorVector,
+	OpV128AndNot:                OperatorVector,
+	OpV128Or:                    OperatorVector,
+	OpV128Xor:                   OperatorVector,
+	OpV128Bitselect:             OperatorVector,
+	OpV128AnyTrue:               OperatorVector,
+	OpI8x16Abs:                  OperatorVector,
+	OpI8x16Neg:                  OperatorVector,
+	OpI8x16AllTrue:              OperatorVector,
+	OpI8x16Bitmask:              OperatorVector,
+	OpI8x16Shl:                  OperatorVector,
+	OpI8x16ShrS:                 OperatorVector,
+	OpI8x16ShrU:                 OperatorVector,
+	OpI8x16Add:                  OperatorVector,
+	OpI8x16AddSatS:              OperatorVector,
+	OpI8x16AddSatU:              OperatorVector,
+	OpI8x16Sub:                  OperatorVector,
+	OpI8x16SubSatS:              OperatorVector,
+	OpI8x16SubSatU:              OperatorVector,
+	OpI8x16MinS:                 OperatorVector,
+	OpI8x16MinU:                 OperatorVector,
+	OpI8x16MaxS:                 OperatorVector,
+	OpI8x16MaxU:                 OperatorVector,
+	OpI8x16Popcnt:               OperatorVector,
+	OpI16x8Abs:                  OperatorVector,
+	OpI16x8Neg:                  OperatorVector,
+	OpI16x8AllTrue:              OperatorVector,
+	OpI16x8Bitmask:              OperatorVector,
+	OpI16x8Shl:                  OperatorVector,
+	OpI16x8ShrS:                 OperatorVector,
+	OpI16x8ShrU:                 OperatorVector,
+	OpI16x8Add:                  OperatorVector,
+	OpI16x8AddSatS:              OperatorVector,
+	OpI16x8AddSatU:              OperatorVector,
+	OpI16x8Sub:                  OperatorVector,
+	OpI16x8SubSatS:              OperatorVector,
+	OpI16x8SubSatU:              OperatorVector,
+	OpI16x8Mul:                  OperatorVector,
+	OpI16x8MinS:                 OperatorVector,
+	OpI16x8MinU:                 OperatorVector,
+	OpI16x8MaxS:                 OperatorVector,
+	OpI16x8MaxU:                 OperatorVector,
+	OpI16x8ExtAddPairwiseI8x16S: OperatorVector,
+	OpI16x8ExtAddPairwiseI8x16U: OperatorVector,
+	OpI32x4Abs:                  OperatorVector,
+	OpI32x4Neg:                  OperatorVector,
+	OpI32x4AllTrue:              OperatorVector,
+	OpI32x4Bitmask:              OperatorVector,
+	OpI32x4Shl:                  OperatorVector,
+	OpI32x4ShrS:                 OperatorVector,
+	OpI32x4ShrU:                 OperatorVector,
+	OpI32x4Add:                  OperatorVector,
+	OpI32x4Sub:                  OperatorVector,
+	OpI32x4Mul:                  OperatorVector,
+	OpI32x4MinS:                 OperatorVector,
+	OpI32x4MinU:                 OperatorVector,
+	OpI32x4MaxS:                 OperatorVector,
+	OpI32x4MaxU:                 OperatorVector,
+	OpI32x4DotI16x8S:            OperatorVector,
+	OpI32x4ExtAddPairwiseI16x8S: OperatorVector,
+	OpI32x4ExtAddPairwiseI16x8U: OperatorVector,
+	OpI64x2Abs:                  OperatorVector,
+	OpI64x2Neg:                  OperatorVector,
+	OpI64x2AllTrue:              OperatorVector,
+	OpI64x2Bitmask:              OperatorVector,
+	OpI64x2Shl:                  OperatorVector,
+	OpI64x2ShrS:                 OperatorVector,
+	OpI64x2ShrU:                 OperatorVector,
+	OpI64x2Add:                  OperatorVector,
+	OpI64x2Sub:                  OperatorVector,
+	OpI64x2Mul:                  OperatorVector,
+	OpF32x4Ceil:                 OperatorVector,
+	OpF32x4Floor:                OperatorVector,
+	OpF32x4Trunc:                OperatorVector,
+	OpF32x4Nearest:              OperatorVector,
+	OpF64x2Ceil:                 OperatorVector,
+	OpF64x2Floor:                OperatorVector,
+	OpF64x2Trunc:                OperatorVector,
+	OpF64x2Nearest:              OperatorVector,
+	OpF32x4Abs:                  OperatorVector,
+	OpF32x4Neg:                  OperatorVector,
+	OpF32x4Sqrt:                 OperatorVector,
+	OpF32x4Add:                  OperatorVector,
+	OpF32x4Sub:                  OperatorVector,
+	OpF32x4Mul:                  OperatorVector,
+	OpF32x4Div:                  OperatorVector,
+	OpF32x4Min:                  OperatorVector,
+	OpF32x4Max:                  OperatorVector,
+	OpF32x4PMin:                 OperatorVector,
+	OpF32x4PMax:                 OperatorVector,
+	OpF64x2Abs:                  OperatorVector,
+	OpF64x2Neg:                  OperatorVector,
+	OpF64x2Sqrt:                 OperatorVector,
+	OpF64x2Add:                  OperatorVector,
+	OpF64x2Sub:                  OperatorVector,
+	OpF64x2Mul:                  OperatorVector,
+	OpF64x2Div:                  OperatorVector,
+	OpF64x2Min:                  OperatorVector,
+	OpF64x2Max:                  OperatorVector,
+	OpF64x2PMin:                 OperatorVector,
+	OpF64x2PMax:                 OperatorVector,
+	OpI32x4TruncSatF32x4S:       OperatorVector,
+	OpI32x4TruncSatF32x4U:       OperatorVector,
+	OpF32x4ConvertI32x4S:        OperatorVector,
+	OpF32x4ConvertI32x4U:        OperatorVector,
+	OpI8x16Swizzle:              OperatorVector,
+	OpI8x16Shuffle:              OperatorVector,
+	OpV128Load8Splat:            OperatorLoad,
+	OpV128Load16Splat:           OperatorLoad,
+	OpV128Load32Splat:           OperatorLoad,
+	OpV128Load32Zero:            OperatorLoad,
+	OpV128Load64Splat:           OperatorLoad,
+	OpV128Load64Zero:            OperatorLoad,
+	OpI8x16NarrowI16x8S:         OperatorVector,
+	OpI8x16NarrowI16x8U:         OperatorVector,
+	OpI16x8NarrowI32x4S:         OperatorVector,
+	OpI16x8NarrowI32x4U:         OperatorVector,
+	OpI16x8ExtendLowI8x16S:      OperatorVector,
+	OpI16x8ExtendHighI8x16S:     OperatorVector,
+	OpI16x8ExtendLowI8x16U:      OperatorVector,
+	OpI16x8ExtendHighI8x16U:     OperatorVector,
+	OpI32x4ExtendLowI16x8S:      OperatorVector,
+	OpI32x4ExtendHighI16x8S:     OperatorVector,
+	OpI32x4ExtendLowI16x8U:      OperatorVector,
+	OpI32x4ExtendHighI16x8U:     OperatorVector,
+	OpI64x2ExtendLowI32x4S:      OperatorVector,
+	OpI64x2ExtendHighI32x4S:     OperatorVector,
+	OpI64x2ExtendLowI32x4U:      OperatorVector,
+	OpI64x2ExtendHighI32x4U:     OperatorVector,
+	OpI16x8ExtMulLowI8x16S:      OperatorVector,
+	OpI16x8ExtMulHighI8x16S:     OperatorVector,
+	OpI16x8ExtMulLowI8x16U:      OperatorVector,
+	OpI16x8ExtMulHighI8x16U:     OperatorVector,
+	OpI32x4ExtMulLowI16x8S:      OperatorVector,
+	OpI32x4ExtMulHighI16x8S:     OperatorVector,
+	OpI32x4ExtMulLowI16x8U:      OperatorVector,
+	OpI32x4ExtMulHighI16x8U:     OperatorVector,
+	OpI64x2ExtMulLowI32x4S:      OperatorVector,
+	OpI64x2ExtMulHighI32x4S:     OperatorVector,
+	OpI64x2ExtMulLowI32x4U:      OperatorVector,
+	OpI64x2ExtMulHighI32x4U:     OperatorVector,
+	OpV128Load8x8S:              OperatorLoad,
+	OpV128Load8x8U:              OperatorLoad,
+	OpV128Load16x4S:             OperatorLoad,
+	OpV128Load16x4U:             OperatorLoad,
+	OpV128Load32x2S:             OperatorLoad,
+	OpV128Load32x2U:             OperatorLoad,
+	OpV128Load8Lane:             OperatorLoad,
+	OpV128Load16Lane:            OperatorLoad,
+	OpV128Load32Lane:            OperatorLoad,
+	OpV128Load64Lane:            OperatorLoad,
+	OpV128Store8Lane:            OperatorStore,
+	OpV128Store16Lane:           OperatorStore,
+	OpV128Store32Lane:           OperatorStore,
+	OpV128Store64Lane:           OperatorStore,
+	OpI8x16RoundingAverageU:     OperatorVector,
+	OpI16x8RoundingAverageU:     OperatorVector,
+	OpI16x8Q15MulrSatS:          OperatorVector,
+	OpF32x4DemoteF64x2Zero:      OperatorVector,
+	OpF64x2PromoteLowF32x4:      OperatorVector,
+	OpF64x2ConvertLowI32x4S:     OperatorVector,
+	OpF64x2ConvertLowI32x4U:     OperatorVector,
+	OpI32x4TruncSatF64x2SZero:   OperatorVector,
+	OpI32x4TruncSatF64x2UZero:   OperatorVector,
+}
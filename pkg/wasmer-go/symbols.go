@@ -0,0 +1,203 @@
+package wasmer
+
+import (
+	"debug/dwarf"
+	"strings"
+	"sync"
+)
+
+// moduleSymbols are the names and source locations of the functions
+// of a module, read from its bytes to resolve the frames of a trap.
+type moduleSymbols struct {
+	// Function names by index, from the `name` custom section.
+	names map[uint32]string
+	// The first name a function is exported with, used for the
+	// functions the `name` section doesn't name.
+	exports map[uint32]string
+	// The offset of the content of the code section, which the DWARF
+	// addresses are relative to.
+	codeOffset uint64
+	// The `.debug_*` custom sections, by name.
+	debugSections map[string][]byte
+
+	dwarfOnce sync.Once
+	dwarf     *dwarf.Data
+}
+
+// newModuleSymbols reads the symbols of the module; whatever follows a
+// malformed section is ignored, the symbols are best effort.
+func newModuleSymbols(wasmBytes []byte) *moduleSymbols {
+	self := &moduleSymbols{
+		names:         make(map[uint32]string),
+		exports:       make(map[uint32]string),
+		debugSections: make(map[string][]byte),
+	}
+
+	sections, _ := ReadWasmSections(wasmBytes)
+
+	for _, section := range sections {
+		switch section.ID {
+		case 0:
+			self.readCustomSection(NewWasmReader(section.Content))
+		case 7:
+			self.readExportSection(NewWasmReader(section.Content))
+		case 10:
+			self.codeOffset = uint64(section.Offset)
+		}
+	}
+
+	return self
+}
+
+func (self *moduleSymbols) readCustomSection(section *WasmReader) {
+	name, err := section.Name()
+
+	if err != nil {
+		return
+	}
+
+	switch {
+	case name == "name":
+		self.readNameSection(section)
+	case strings.HasPrefix(name, ".debug_"):
+		self.debugSections[name] = section.bytes[section.Offset():]
+	}
+}
+
+func (self *moduleSymbols) readNameSection(section *WasmReader) {
+	for !section.EOF() {
+		id, err := section.Byte()
+
+		if err != nil {
+			return
+		}
+
+		content, err := section.Vector()
+
+		if err != nil {
+			return
+		}
+
+		// Only the function names are used.
+		if id != 1 {
+			continue
+		}
+
+		subsection := NewWasmReader(content)
+		count, err := subsection.U32()
+
+		for nth := uint32(0); err == nil && nth < count; nth++ {
+			var index uint32
+			var name string
+
+			if index, err = subsection.U32(); err == nil {
+				if name, err = subsection.Name(); err == nil {
+					self.names[index] = name
+				}
+			}
+		}
+	}
+}
+
+func (self *moduleSymbols) readExportSection(section *WasmReader) {
+	count, err := section.U32()
+
+	for nth := uint32(0); err == nil && nth < count; nth++ {
+		var name string
+		var kind byte
+		var index uint32
+
+		if name, err = section.Name(); err != nil {
+			return
+		}
+
+		if kind, err = section.Byte(); err != nil {
+			return
+		}
+
+		if index, err = section.U32(); err != nil {
+			return
+		}
+
+		if _, exists := self.exports[index]; kind == 0 && !exists {
+			self.exports[index] = name
+		}
+	}
+}
+
+// functionName returns the name of the function, or "" if it has
+// none.
+func (self *moduleSymbols) functionName(index uint32) string {
+	if name, exists := self.names[index]; exists {
+		return name
+	}
+
+	return self.exports[index]
+}
+
+// location returns the source file and line of the instruction at
+// moduleOffset, from the DWARF sections.
+func (self *moduleSymbols) location(moduleOffset uint64) (string, int, bool) {
+	self.dwarfOnce.Do(func() {
+		self.dwarf = self.newDwarf()
+	})
+
+	if self.dwarf == nil || moduleOffset < self.codeOffset {
+		return "", 0, false
+	}
+
+	address := moduleOffset - self.codeOffset
+	unit, err := self.dwarf.Reader().SeekPC(address)
+
+	if err != nil {
+		return "", 0, false
+	}
+
+	lines, err := self.dwarf.LineReader(unit)
+
+	if err != nil || lines == nil {
+		return "", 0, false
+	}
+
+	var line dwarf.LineEntry
+
+	if lines.SeekPC(address, &line) != nil || line.File == nil {
+		return "", 0, false
+	}
+
+	return line.File.Name, line.Line, true
+}
+
+func (self *moduleSymbols) newDwarf() *dwarf.Data {
+	sections := self.debugSections
+
+	if sections[".debug_info"] == nil {
+		return nil
+	}
+
+	data, err := dwarf.New(
+		sections[".debug_abbrev"],
+		sections[".debug_aranges"],
+		sections[".debug_frame"],
+		sections[".debug_info"],
+		sections[".debug_line"],
+		sections[".debug_pubnames"],
+		sections[".debug_ranges"],
+		sections[".debug_str"],
+	)
+
+	if err != nil {
+		return nil
+	}
+
+	// The sections of DWARF 5.
+	for _, name := range []string{".debug_addr", ".debug_line_str", ".debug_str_offsets", ".debug_rnglists", ".debug_loclists"} {
+		if section, exists := sections[name]; exists {
+			if data.AddSection(name, section) != nil {
+				return nil
+			}
+		}
+	}
+
+	return data
+}
@@ -2,7 +2,10 @@ package wasmer
 
 // #include <wasmer.h>
 import "C"
-import "runtime"
+import (
+	"fmt"
+	"runtime"
+)
 
 // TableSize represents the size of a table.
 type TableSize C.wasm_table_size_t
@@ -39,6 +42,44 @@ func newTable(pointer *C.wasm_table_t, ownedBy interface{}) *Table {
 	return table
 }
 
+// NewTable instantiates a new Table in the given Store.
+//
+// It takes three arguments, the Store, the TableType of the Table,
+// and the Function every element initially refers to, nil for none.
+// Only tables of FuncRef elements are supported.
+//
+//   limits, _ := wasmer.NewLimits(2, 10)
+//   table, _ := wasmer.NewTable(
+//       store,
+//       wasmer.NewTableType(wasmer.NewValueType(wasmer.FuncRef), limits),
+//       nil,
+//   )
+//
+func NewTable(store *Store, ty *TableType, init *Function) (*Table, error) {
+	if kind := ty.ValueType().Kind(); kind != FuncRef {
+		return nil, newErrorWith(fmt.Sprintf("table elements of type %s are not supported, only funcref", kind))
+	}
+
+	var table *Table
+
+	err := maybeNewErrorFromWasmer(func() bool {
+		pointer := C.wasm_table_new(store.inner(), ty.inner(), functionAsRef(init))
+		table = newTable(pointer, nil)
+
+		return pointer == nil
+	})
+
+	runtime.KeepAlive(store)
+	runtime.KeepAlive(ty)
+	runtime.KeepAlive(init)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return table, nil
+}
+
 func (self *Table) inner() *C.wasm_table_t {
 	return self._inner
 }
@@ -60,6 +101,121 @@ func (self *Table) Size() TableSize {
 	return TableSize(C.wasm_table_size(self.inner()))
 }
 
+// Type returns the Table's TableType.
+//
+//   table, _ := instance.Exports.GetTable("exported_table")
+//   ty := table.Type()
+//
+func (self *Table) Type() *TableType {
+	ty := C.wasm_table_type(self.inner())
+
+	runtime.KeepAlive(self)
+
+	return newTableType(ty, self.ownedBy())
+}
+
+// Get returns the Function the element at index refers to, nil if the
+// element is null. It returns an Error if index is out of bounds.
+//
+//   table, _ := instance.Exports.GetTable("exported_table")
+//   function, _ := table.Get(0)
+//
+//   if function != nil {
+//       _, _ = function.Call()
+//   }
+//
+func (self *Table) Get(index uint32) (*Function, error) {
+	if err := self.checkIndex(index); err != nil {
+		return nil, err
+	}
+
+	ref := C.wasm_table_get(self.inner(), C.wasm_table_size_t(index))
+
+	runtime.KeepAlive(self)
+
+	if ref == nil {
+		return nil, nil
+	}
+
+	defer C.wasm_ref_delete(ref)
+
+	return newFunctionFromRef(ref), nil
+}
+
+// newFunctionFromRef returns a Function owning a copy of the function
+// ref refers to; ref is still owned by the caller. The func of
+// `wasm_ref_as_func` is only a view of ref, which must not outlive it
+// nor be deleted as a func.
+func newFunctionFromRef(ref *C.wasm_ref_t) *Function {
+	function := C.wasm_ref_as_func(ref)
+
+	if function == nil {
+		return nil
+	}
+
+	return newFunction(C.wasm_func_copy(function), nil, nil)
+}
+
+// Set makes the element at index refer to function, or null if
+// function is nil. It returns an Error if index is out of bounds.
+//
+// A host Function can be installed this way, so the guest calls it
+// with `call_indirect`.
+//
+//   table, _ := instance.Exports.GetTable("exported_table")
+//   err := table.Set(0, hostFunction)
+//
+func (self *Table) Set(index uint32, function *Function) error {
+	if err := self.checkIndex(index); err != nil {
+		return err
+	}
+
+	err := maybeNewErrorFromWasmer(func() bool {
+		return false == C.wasm_table_set(self.inner(), C.wasm_table_size_t(index), functionAsRef(function))
+	})
+
+	runtime.KeepAlive(self)
+	runtime.KeepAlive(function)
+
+	return err
+}
+
+// Grow grows the Table's size by delta elements, which refer to init,
+// or are null if init is nil. It returns false if the Table can't
+// grow beyond its maximum.
+//
+//   table, _ := instance.Exports.GetTable("exported_table")
+//   grown := table.Grow(2, nil)
+//
+func (self *Table) Grow(delta uint32, init *Function) bool {
+	grown := C.wasm_table_grow(self.inner(), C.wasm_table_size_t(delta), functionAsRef(init))
+
+	runtime.KeepAlive(self)
+	runtime.KeepAlive(init)
+
+	return bool(grown)
+}
+
+func (self *Table) checkIndex(index uint32) error {
+	size := uint32(self.Size())
+
+	if index >= size {
+		return newErrorWith(fmt.Sprintf("table index %d out of bounds, size = %d", index, size))
+	}
+
+	return nil
+}
+
+// functionAsRef returns the reference to function, which is borrowed,
+// or a null reference if function is nil.
+func functionAsRef(function *Function) *C.wasm_ref_t {
+	if function == nil {
+		return nil
+	}
+
+	return C.wasm_func_as_ref(function.inner())
+}
+
 // IntoExtern converts the Table into an Extern.
 //
 //   table, _ := instance.Exports.GetTable("exported_table")
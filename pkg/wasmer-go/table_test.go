@@ -0,0 +1,106 @@
+package wasmer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const tableWat = `(module
+	(type $answer (func (result i32)))
+	(table (export "table") 2 4 funcref)
+	(func $forty_two (type $answer) i32.const 42)
+	(elem (i32.const 0) $forty_two)
+	(func (export "call") (param i32) (result i32)
+		local.get 0
+		call_indirect (type $answer)))`
+
+func testGetTableInstance(t *testing.T) (*Store, *Instance) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	module, err := NewModuleFromWat(store, tableWat, nil)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, NewImportObject())
+	assert.NoError(t, err)
+
+	return store, instance
+}
+
+func TestTableGet(t *testing.T) {
+	_, instance := testGetTableInstance(t)
+
+	table, err := instance.Exports.GetTable("table")
+	assert.NoError(t, err)
+	assert.Equal(t, TableSize(2), table.Size())
+	assert.Equal(t, FuncRef, table.Type().ValueType().Kind())
+
+	function, err := table.Get(0)
+	assert.NoError(t, err)
+	assert.NotNil(t, function)
+
+	result, err := function.Call()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(42), result)
+
+	function, err = table.Get(1)
+	assert.NoError(t, err)
+	assert.Nil(t, function)
+
+	_, err = table.Get(2)
+	assert.Error(t, err)
+}
+
+func TestTableSetHostFunction(t *testing.T) {
+	store, instance := testGetTableInstance(t)
+
+	table, err := instance.Exports.GetTable("table")
+	assert.NoError(t, err)
+
+	host := NewFunction(
+		store,
+		NewFunctionType(NewValueTypes(), NewValueTypes(I32)),
+		func(args []Value) ([]Value, error) {
+			return []Value{NewI32(7)}, nil
+		},
+	)
+	assert.NoError(t, table.Set(1, host))
+
+	call, err := instance.Exports.GetFunction("call")
+	assert.NoError(t, err)
+
+	result, err := call(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(7), result)
+
+	assert.Error(t, table.Set(4, host))
+}
+
+func TestTableGrow(t *testing.T) {
+	_, instance := testGetTableInstance(t)
+
+	table, err := instance.Exports.GetTable("table")
+	assert.NoError(t, err)
+
+	assert.True(t, table.Grow(2, nil))
+	assert.Equal(t, TableSize(4), table.Size())
+
+	function, err := table.Get(3)
+	assert.NoError(t, err)
+	assert.Nil(t, function)
+
+	assert.False(t, table.Grow(1, nil))
+}
+
+func TestNewTable(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	limits, err := NewLimits(1, 2)
+	assert.NoError(t, err)
+
+	table, err := NewTable(store, NewTableType(NewValueType(FuncRef), limits), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, TableSize(1), table.Size())
+
+	_, err = NewTable(store, NewTableType(NewValueType(I32), limits), nil)
+	assert.Error(t, err)
+}
@@ -2,23 +2,17 @@ package wasmer
 
 // #include <wasmer.h>
 import "C"
-import "runtime"
+import (
+	"runtime"
+	"strings"
+)
 
 // Target represents a triple + CPU features pairs.
 type Target struct {
-	_inner *C.wasmer_target_t
-}
-
-func newTarget(target *C.wasmer_target_t) *Target {
-	self := &Target{
-		_inner: target,
-	}
-
-	runtime.SetFinalizer(self, func(self *Target) {
-		C.wasmer_target_delete(self.inner())
-	})
-
-	return self
+	triple      *Triple
+	cpuFeatures *CpuFeatures
+	// The triple and the CPU features, see Config.Profile.
+	description string
 }
 
 // NewTarget creates a new target.
@@ -27,22 +21,58 @@ func newTarget(target *C.wasmer_target_t) *Target {
 //  cpuFeatures := NewCpuFeatures()
 //  target := NewTarget(triple, cpuFeatures)
 func NewTarget(triple *Triple, cpuFeatures *CpuFeatures) *Target {
-	return newTarget(C.wasmer_target_new(triple.inner(), cpuFeatures.inner()))
+	description := triple.name
+
+	if len(cpuFeatures.names) > 0 {
+		description += "+" + strings.Join(cpuFeatures.names, ",")
+	}
+
+	return &Target{
+		triple:      triple,
+		cpuFeatures: cpuFeatures,
+		description: description,
+	}
 }
 
+// inner creates the C target, from a new C triple and new C CPU
+// features; its ownership goes to the C configuration it is set on,
+// so a Target can be set on any number of them.
 func (self *Target) inner() *C.wasmer_target_t {
-	return self._inner
+	var triple *C.wasmer_triple_t
+
+	if self.triple.fromHost {
+		triple = C.wasmer_triple_new_from_host()
+	} else {
+		cTripleName := newName(self.triple.name)
+		defer C.wasm_name_delete(&cTripleName)
+
+		triple = C.wasmer_triple_new(&cTripleName)
+	}
+
+	cpuFeatures := C.wasmer_cpu_features_new()
+
+	for _, name := range self.cpuFeatures.names {
+		cFeature := newName(name)
+		C.wasmer_cpu_features_add(cpuFeatures, &cFeature)
+		C.wasm_name_delete(&cFeature)
+	}
+
+	return C.wasmer_target_new(triple, cpuFeatures)
 }
 
 // Triple; historically such things had three fields, though they have
 // added additional fields over time.
 type Triple struct {
 	_inner *C.wasmer_triple_t
+	name   string
+	// Created by NewTripleFromHost.
+	fromHost bool
 }
 
-func newTriple(triple *C.wasmer_triple_t) *Triple {
+func newTriple(triple *C.wasmer_triple_t, name string) *Triple {
 	self := &Triple{
 		_inner: triple,
+		name:   name,
 	}
 
 	runtime.SetFinalizer(self, func(self *Triple) {
@@ -63,7 +93,7 @@ func NewTriple(triple string) (*Triple, error) {
 	var cTriple *C.wasmer_triple_t
 
 	err := maybeNewErrorFromWasmer(func() bool {
-		cTriple := C.wasmer_triple_new(&cTripleName)
+		cTriple = C.wasmer_triple_new(&cTripleName)
 
 		return cTriple == nil
 	})
@@ -72,12 +102,15 @@ func NewTriple(triple string) (*Triple, error) {
 		return nil, err
 	}
 
-	return newTriple(cTriple), nil
+	return newTriple(cTriple, triple), nil
 }
 
 // NewTripleFromHost creates a new triple from the current host.
 func NewTripleFromHost() *Triple {
-	return newTriple(C.wasmer_triple_new_from_host())
+	self := newTriple(C.wasmer_triple_new_from_host(), "host")
+	self.fromHost = true
+
+	return self
 }
 
 func (self *Triple) inner() *C.wasmer_triple_t {
@@ -123,6 +156,8 @@ func (self *Triple) inner() *C.wasmer_triple_t {
 // • lzcnt.
 type CpuFeatures struct {
 	_inner *C.wasmer_cpu_features_t
+	// The features added, in order.
+	names []string
 }
 
 func newCpuFeatures(cpu_features *C.wasmer_cpu_features_t) *CpuFeatures {
@@ -156,6 +191,8 @@ func (self *CpuFeatures) Add(feature string) error {
 		return err
 	}
 
+	self.names = append(self.names, feature)
+
 	return nil
 }
 
@@ -13,6 +13,12 @@ func TestTarget(t *testing.T) {
 	cpuFeatures.Add("sse2")
 
 	target := NewTarget(triple, cpuFeatures)
+	assert.Equal(t, "x86_64-apple-darwin+sse2", target.description)
 
-	_ = target
+	// every Config gets its own C target
+	config := NewConfig().UseTarget(target)
+	for nth := 0; nth < 2; nth++ {
+		_, err := NewEngineWithConfig(config)
+		assert.NoError(t, err)
+	}
 }
\ No newline at end of file
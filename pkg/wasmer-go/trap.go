@@ -14,6 +14,7 @@ package wasmer
 // }
 import "C"
 import (
+	"fmt"
 	"runtime"
 	"unsafe"
 )
@@ -115,6 +116,9 @@ func (self *Trap) Trace() *Trace {
 type Frame struct {
 	_inner   *C.wasm_frame_t
 	_ownedBy interface{}
+	// Set when the frame is part of a TrapError, if known.
+	symbols *moduleSymbols
+	exports *Exports
 }
 
 func newFrame(pointer *C.wasm_frame_t, ownedBy interface{}) *Frame {
@@ -165,9 +169,68 @@ func (self *Frame) FunctionOffset() uint {
 	return uint(index)
 }
 
-func (self *Frame) Instance() {
-	//TODO: See https://github.com/wasmerio/wasmer/blob/6fbc903ea32774c830fd9ee86140d1406ac5d745/lib/c-api/src/wasm_c_api/types/frame.rs#L31-L34
-	panic("to do!")
+// Instance returns the Instance of the function that was called when
+// the trap happened, or nil if unknown, e.g. for a trap of a start
+// function. Wasmer doesn't tell the instance of each frame, so a frame
+// of a function imported from another instance returns it too.
+//
+// The returned Instance is only valid as long as the called Instance
+// is not closed, and closing it does nothing.
+//
+//   memory, _ := trapError.Origin().Instance().Exports.GetMemory("memory")
+func (self *Frame) Instance() *Instance {
+	if self.exports == nil {
+		return nil
+	}
+
+	return &Instance{
+		_inner:   self.exports.instance,
+		Exports:  self.exports,
+		borrowed: true,
+	}
+}
+
+// FunctionName returns the name of the function of the frame, from
+// the `name` custom section of the module, or else the name the
+// function is exported with; "" if neither is known.
+func (self *Frame) FunctionName() string {
+	if self.symbols == nil {
+		return ""
+	}
+
+	return self.symbols.functionName(self.FunctionIndex())
+}
+
+// Location returns the source file and line of the frame, from the
+// DWARF custom sections of the module if it has some.
+//
+//   if file, line, ok := frame.Location(); ok {
+//   	fmt.Printf("%s:%d\n", file, line)
+//   }
+func (self *Frame) Location() (string, int, bool) {
+	if self.symbols == nil {
+		return "", 0, false
+	}
+
+	return self.symbols.location(uint64(self.ModuleOffset()))
+}
+
+// String returns the function name of the frame and its source
+// location, or its module offset if it has none.
+//
+//   frame.String() // "increase at src/lib.rs:12" or "func[3] at offset 0x1a2b"
+func (self *Frame) String() string {
+	name := self.FunctionName()
+
+	if name == "" {
+		name = fmt.Sprintf("func[%d]", self.FunctionIndex())
+	}
+
+	if file, line, ok := self.Location(); ok {
+		return fmt.Sprintf("%s at %s:%d", name, file, line)
+	}
+
+	return fmt.Sprintf("%s at offset %#x", name, self.ModuleOffset())
 }
 
 // ModuleOffset returns the byte offset from the beginning of the
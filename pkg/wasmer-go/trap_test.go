@@ -17,3 +17,67 @@ func TestTrap(t *testing.T) {
 	assert.NotNil(t, trap.Trace())
 	assert.Len(t, trap.Trace().frames, 0)
 }
+
+const trapErrorWat = `(module
+	(func $divide (param i32) (result i32)
+		i32.const 1
+		local.get 0
+		i32.div_s)
+	(func (export "run") (param i32) (result i32)
+		local.get 0
+		call $divide)
+	(func (export "fail") unreachable))`
+
+func TestTrapError(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	module, err := NewModuleFromWat(store, trapErrorWat, nil)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, NewImportObject())
+	assert.NoError(t, err)
+
+	run, err := instance.Exports.GetFunction("run")
+	assert.NoError(t, err)
+
+	_, err = run(0)
+	trapError, ok := err.(*TrapError)
+	assert.True(t, ok)
+	assert.Equal(t, TrapDivisionByZero, trapError.Kind())
+
+	trace := trapError.Trace()
+	assert.Len(t, trace, 2)
+	assert.Equal(t, "divide", trace[0].FunctionName())
+	assert.Equal(t, "run", trace[1].FunctionName())
+	assert.Contains(t, trapError.Backtrace(), "#0 divide at offset 0x")
+	assert.NotNil(t, trapError.Origin().Instance().Exports)
+
+	fail, err := instance.Exports.GetFunction("fail")
+	assert.NoError(t, err)
+
+	_, err = fail()
+	assert.Equal(t, TrapUnreachable, err.(*TrapError).Kind())
+
+	// a deserialized module gets its symbols from the module it was
+	// compiled from
+	serialized, err := module.Serialize()
+	assert.NoError(t, err)
+	deserialized, err := DeserializeModuleWithSymbols(store, serialized, []byte(trapErrorWat))
+	assert.NoError(t, err)
+
+	instance, err = NewInstance(deserialized, NewImportObject())
+	assert.NoError(t, err)
+
+	run, err = instance.Exports.GetFunction("run")
+	assert.NoError(t, err)
+
+	_, err = run(0)
+	assert.Equal(t, "divide", err.(*TrapError).Origin().FunctionName())
+}
+
+func TestTrapKind(t *testing.T) {
+	assert.Equal(t, TrapOutOfBounds, trapKindOf("out of bounds memory access"))
+	assert.Equal(t, TrapStackOverflow, trapKindOf("call stack exhausted"))
+	assert.Equal(t, TrapOther, trapKindOf("host function failed"))
+	assert.Equal(t, "div-by-zero", TrapDivisionByZero.String())
+}
@@ -21,9 +21,24 @@ package wasmer
 // wasm_ref_t *to_ref(wasm_val_t *value) {
 //     return value->of.ref;
 // }
+//
+// void set_ref(wasm_val_t *value, wasm_ref_t *ref) {
+//     value->of.ref = ref;
+// }
+//
+// extern void extern_ref_finalizer(void *handle);
+//
+// void set_extern_ref_handle(wasm_foreign_t *foreign, uintptr_t handle) {
+//     wasm_foreign_set_host_info_with_finalizer(foreign, (void *) handle, extern_ref_finalizer);
+// }
+//
+// uintptr_t get_extern_ref_handle(const wasm_ref_t *ref) {
+//     return (uintptr_t) wasm_ref_get_host_info(ref);
+// }
 import "C"
 import (
 	"fmt"
+	"runtime"
 	"unsafe"
 )
 
@@ -33,7 +48,10 @@ import (
 //
 // • Floating-point (32 or 64 bit width),
 //
-// • Vectors (128 bits, with 32 or 64 bit lanes).
+// • Vectors (128 bits, with 32 or 64 bit lanes),
+//
+// • References to a Function (FuncRef) or to a Go object (AnyRef, see
+// ExternRef).
 //
 // See Also
 //
@@ -59,6 +77,13 @@ func NewValue(value interface{}, kind ValueKind) Value {
 		panic(fmt.Sprintf("Cannot create a Wasm `%s` value from `%T`", err, value))
 	}
 
+	if kind.IsReference() {
+		// The Value owns a copy of the reference.
+		runtime.SetFinalizer(&output, func(output *C.wasm_val_t) {
+			C.wasm_val_delete(output)
+		})
+	}
+
 	return newValue(&output)
 }
 
@@ -102,6 +127,22 @@ func NewF64(value interface{}) Value {
 	return NewValue(value, F64)
 }
 
+// NewAnyRef instantiates a new AnyRef Value, an `externref`, with the
+// given ExternRef; nil is the null reference.
+//
+//   value := NewAnyRef(NewExternRef(store, iterator))
+func NewAnyRef(value *ExternRef) Value {
+	return NewValue(value, AnyRef)
+}
+
+// NewFuncRef instantiates a new FuncRef Value with the given Function;
+// nil is the null reference.
+//
+//   value := NewFuncRef(function)
+func NewFuncRef(value *Function) Value {
+	return NewValue(value, FuncRef)
+}
+
 func (self *Value) inner() *C.wasm_val_t {
 	return self._inner
 }
@@ -189,6 +230,44 @@ func (self *Value) F64() float64 {
 	return float64(C.to_float64(pointer))
 }
 
+// ExternRef returns the Value's value as an ExternRef, nil for the
+// null reference.
+//
+// Note: It panics if the value is not of type AnyRef.
+//
+//   value := NewAnyRef(ref)
+//   _ = value.ExternRef()
+//
+func (self *Value) ExternRef() *ExternRef {
+	pointer := self.inner()
+
+	if ValueKind(pointer.kind) != AnyRef {
+		panic("Cannot convert value to `*ExternRef`")
+	}
+
+	return toGoValue(pointer).(*ExternRef)
+}
+
+// FuncRef returns the Value's value as a Function, nil for the null
+// reference.
+//
+// Note: It panics if the value is not of type FuncRef.
+//
+//   value := NewFuncRef(function)
+//   _ = value.FuncRef()
+//
+func (self *Value) FuncRef() *Function {
+	pointer := self.inner()
+
+	if ValueKind(pointer.kind) != FuncRef {
+		panic("Cannot convert value to `*Function`")
+	}
+
+	return toGoValue(pointer).(*Function)
+}
+
+// toGoValue converts a wasm_val_t to a Go value; a reference is
+// copied, the wasm_val_t still owns its own.
 func toGoValue(pointer *C.wasm_val_t) interface{} {
 	switch ValueKind(pointer.kind) {
 	case I32:
@@ -199,11 +278,31 @@ func toGoValue(pointer *C.wasm_val_t) interface{} {
 		return float32(C.to_float32(pointer))
 	case F64:
 		return float64(C.to_float64(pointer))
+	case AnyRef:
+		ref := C.to_ref(pointer)
+
+		if ref == nil {
+			return (*ExternRef)(nil)
+		}
+
+		return newExternRef(C.wasm_ref_copy(ref))
+	case FuncRef:
+		ref := C.to_ref(pointer)
+
+		if ref == nil {
+			return (*Function)(nil)
+		}
+
+		// The reference is owned by the value, the Function owns a
+		// copy of its function.
+		return newFunctionFromRef(ref)
 	default:
 		panic("to do `newValue`")
 	}
 }
 
+// fromGoValue converts a Go value to a wasm_val_t; a reference is
+// copied, the wasm_val_t owns it.
 func fromGoValue(value interface{}, kind ValueKind) (C.wasm_val_t, error) {
 	output := C.wasm_val_t{}
 
@@ -282,6 +381,41 @@ func fromGoValue(value interface{}, kind ValueKind) (C.wasm_val_t, error) {
 		default:
 			return output, newErrorWith("f64")
 		}
+	case AnyRef:
+		output.kind = kind.inner()
+
+		switch value.(type) {
+		case nil:
+			C.set_ref(&output, nil)
+		case *ExternRef:
+			ref := value.(*ExternRef)
+
+			if ref == nil {
+				C.set_ref(&output, nil)
+			} else {
+				C.set_ref(&output, ref.copyRef())
+			}
+		default:
+			return output, newErrorWith("anyref")
+		}
+	case FuncRef:
+		output.kind = kind.inner()
+
+		switch value.(type) {
+		case nil:
+			C.set_ref(&output, nil)
+		case *Function:
+			function := value.(*Function)
+
+			if function == nil {
+				C.set_ref(&output, nil)
+			} else {
+				C.set_ref(&output, C.wasm_ref_copy(C.wasm_func_as_ref(function.inner())))
+				runtime.KeepAlive(function)
+			}
+		default:
+			return output, newErrorWith("funcref")
+		}
 	default:
 		panic("To do, `fromGoValue`!")
 	}
@@ -0,0 +1,13 @@
+package wasmer
+
+// #include <wasmer.h>
+import "C"
+
+// Version returns the version of the Wasmer runtime wasmer-go is
+// linked against, e.g. "2.1.1".
+//
+//   version := wasmer.Version()
+//
+func Version() string {
+	return C.GoString(C.wasmer_version())
+}
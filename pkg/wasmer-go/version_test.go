@@ -0,0 +1,13 @@
+package wasmer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestVersion(t *testing.T) {
+	version := Version()
+	assert.NotEmpty(t, version)
+	assert.Equal(t, 3, len(strings.SplitN(strings.SplitN(version, "-", 2)[0], ".", 3)))
+}
@@ -1,60 +1,11 @@
 package wasmer
 
 // #include <stdlib.h>
-// #include <stdio.h>
 // #include <wasmer.h>
-//
-// // Buffer size for `wasi_env_read_inner`.
-// #define WASI_ENV_READER_BUFFER_SIZE 1024
-//
-// // Define a type for the WASI environment captured stream readers
-// // (`wasi_env_read_stdout` and `wasi_env_read_stderr`).
-// typedef intptr_t (*wasi_env_reader)(
-//     wasi_env_t* wasi_env,
-//     char* buffer,
-//     uintptr_t buffer_len
-// );
-//
-// // // Common function to read a WASI environment captured stream.
-// // size_t to_wasi_env_read_inner(wasi_env_t *wasi_env, char** buffer, wasi_env_reader reader) {
-// //     FILE *memory_stream;
-// //     size_t buffer_size = 0;
-// //
-// //     memory_stream = open_memstream(buffer, &buffer_size);
-// //
-// //     if (NULL == memory_stream) {
-// //         return 0;
-// //     }
-// //
-// //     char temp_buffer[WASI_ENV_READER_BUFFER_SIZE] = { 0 };
-// //     size_t data_read_size = WASI_ENV_READER_BUFFER_SIZE;
-// //
-// //     do {
-// //         data_read_size = reader(wasi_env, temp_buffer, WASI_ENV_READER_BUFFER_SIZE);
-// //
-// //         if (data_read_size > 0) {
-// //             buffer_size += data_read_size;
-// //             fwrite(temp_buffer, sizeof(char), data_read_size, memory_stream);
-// //         }
-// //     } while (WASI_ENV_READER_BUFFER_SIZE == data_read_size);
-// //
-// //     fclose(memory_stream);
-// //
-// //     return buffer_size;
-// // }
-// //
-// // // Read the captured `stdout`.
-// // size_t to_wasi_env_read_stdout(wasi_env_t *wasi_env, char** buffer) {
-// //     return to_wasi_env_read_inner(wasi_env, buffer, wasi_env_read_stdout);
-// // }
-// //
-// // // Read the captured `stderr`.
-// // size_t to_wasi_env_read_stderr(wasi_env_t *wasi_env, char** buffer) {
-// //     return to_wasi_env_read_inner(wasi_env, buffer, wasi_env_read_stderr);
-// // }
 import "C"
 import (
-	"reflect"
+	"fmt"
+	"io"
 	"runtime"
 	"unsafe"
 )
@@ -109,6 +60,13 @@ func GetWasiVersion(module *Module) WasiVersion {
 // WasiStateBuilder is a convenient API for configuring WASI.
 type WasiStateBuilder struct {
 	_inner *C.wasi_config_t
+	// Where Flush copies the captured stdout and stderr, if set.
+	stdoutWriter io.Writer
+	stderrWriter io.Writer
+	sandbox      *WasiSandbox
+	// The directories of PreopenDirectory and MapDirectory, which a
+	// sandbox refuses.
+	hostDirectories []string
 }
 
 // NewWasiStateBuilder creates a new WASI state builder, starting by
@@ -170,6 +128,7 @@ func (self *WasiStateBuilder) PreopenDirectory(preopenDirectory string) *WasiSta
 	defer C.free(unsafe.Pointer(cPreopenDirectory))
 
 	C.wasi_config_preopen_dir(self.inner(), cPreopenDirectory)
+	self.hostDirectories = append(self.hostDirectories, preopenDirectory)
 
 	return self
 }
@@ -189,6 +148,7 @@ func (self *WasiStateBuilder) MapDirectory(alias string, directory string) *Wasi
 	defer C.free(unsafe.Pointer(cDirectory))
 
 	C.wasi_config_mapdir(self.inner(), cAlias, cDirectory)
+	self.hostDirectories = append(self.hostDirectories, directory)
 
 	return self
 }
@@ -214,6 +174,20 @@ func (self *WasiStateBuilder) CaptureStdout() *WasiStateBuilder {
 	return self
 }
 
+// CaptureStdoutTo configures the WASI module to capture its stdout,
+// which WasiEnvironment.Flush copies to writer. NewLogWriter routes
+// it to a logger. The writer is closed by
+// WasiEnvironment.CloseWriters and RunWasi if it is an io.Closer.
+//
+//    var stdout bytes.Buffer
+//    wasiStateBuilder := NewWasiStateBuilder("test-program").
+//    	CaptureStdoutTo(&stdout)
+func (self *WasiStateBuilder) CaptureStdoutTo(writer io.Writer) *WasiStateBuilder {
+	self.stdoutWriter = writer
+
+	return self.CaptureStdout()
+}
+
 // InheritStdout configures the WASI module to inherit the stdout from
 // the host.
 func (self *WasiStateBuilder) InheritStdout() *WasiStateBuilder {
@@ -229,6 +203,14 @@ func (self *WasiStateBuilder) CaptureStderr() *WasiStateBuilder {
 	return self
 }
 
+// CaptureStderrTo configures the WASI module to capture its stderr,
+// which WasiEnvironment.Flush copies to writer. See CaptureStdoutTo.
+func (self *WasiStateBuilder) CaptureStderrTo(writer io.Writer) *WasiStateBuilder {
+	self.stderrWriter = writer
+
+	return self.CaptureStderr()
+}
+
 // InheritStderr configures the WASI module to inherit the stderr from
 // the host.
 func (self *WasiStateBuilder) InheritStderr() *WasiStateBuilder {
@@ -237,6 +219,19 @@ func (self *WasiStateBuilder) InheritStderr() *WasiStateBuilder {
 	return self
 }
 
+// Sandbox runs the WASI module in sandbox, which gives it a virtual
+// filesystem and deterministic clock and random sources instead of
+// the host ones. See WasiSandbox. It cannot be combined with
+// PreopenDirectory or MapDirectory.
+//
+//    wasiStateBuilder := NewWasiStateBuilder("test-program").
+//    	Sandbox(NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE))
+func (self *WasiStateBuilder) Sandbox(sandbox *WasiSandbox) *WasiStateBuilder {
+	self.sandbox = sandbox
+
+	return self
+}
+
 // Finalize tells the state builder to produce a WasiEnvironment. It
 // consumes the current WasiStateBuilder.
 //
@@ -262,10 +257,28 @@ func (self *WasiStateBuilder) inner() *C.wasi_config_t {
 // user-defined host function; that's the same idea here but applied
 // to WASI functions and other imports).
 type WasiEnvironment struct {
-	_inner *C.wasi_env_t
+	_inner       *C.wasi_env_t
+	stdoutWriter io.Writer
+	stderrWriter io.Writer
+	// The state of the sandbox, if any.
+	sandbox *wasiSandboxState
 }
 
 func newWasiEnvironment(stateBuilder *WasiStateBuilder) (*WasiEnvironment, error) {
+	var sandbox *wasiSandboxState
+
+	if stateBuilder.sandbox != nil {
+		var err error
+
+		if len(stateBuilder.hostDirectories) > 0 {
+			return nil, newErrorWith(fmt.Sprintf("A WASI sandbox cannot open the host directory `%s`", stateBuilder.hostDirectories[0]))
+		}
+
+		if sandbox, err = stateBuilder.sandbox.newState(); err != nil {
+			return nil, err
+		}
+	}
+
 	var environment *C.wasi_env_t
 
 	err := maybeNewErrorFromWasmer(func() bool {
@@ -279,7 +292,10 @@ func newWasiEnvironment(stateBuilder *WasiStateBuilder) (*WasiEnvironment, error
 	}
 
 	self := &WasiEnvironment{
-		_inner: environment,
+		_inner:       environment,
+		stdoutWriter: stateBuilder.stdoutWriter,
+		stderrWriter: stateBuilder.stderrWriter,
+		sandbox:      sandbox,
 	}
 
 	runtime.SetFinalizer(self, func(environment *WasiEnvironment) {
@@ -293,15 +309,73 @@ func (self *WasiEnvironment) inner() *C.wasi_env_t {
 	return self._inner
 }
 
-func buildByteSliceFromCBuffer(buffer *C.char, length int) []byte {
-	var header reflect.SliceHeader
-	header = *(*reflect.SliceHeader)(unsafe.Pointer(&header))
+// wasiStream is one of the captured output streams of a
+// WasiEnvironment.
+type wasiStream int
+
+const (
+	wasiStdout wasiStream = iota
+	wasiStderr
+)
+
+func (self wasiStream) String() string {
+	if self == wasiStdout {
+		return "stdout"
+	}
+
+	return "stderr"
+}
+
+// read moves at most len(buffer) bytes of the captured stream into
+// buffer; it returns io.EOF once nothing is left.
+func (self *WasiEnvironment) read(stream wasiStream, buffer []byte) (int, error) {
+	if self.sandbox != nil {
+		return self.sandbox.read(stream, buffer)
+	}
+
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	bufferPtr := (*C.char)(unsafe.Pointer(&buffer[0]))
+	bufferLength := C.uintptr_t(len(buffer))
+
+	var length C.intptr_t
 
-	header.Data = uintptr(unsafe.Pointer(buffer))
-	header.Len = length
-	header.Cap = length
+	switch stream {
+	case wasiStdout:
+		length = C.wasi_env_read_stdout(self.inner(), bufferPtr, bufferLength)
+	default:
+		length = C.wasi_env_read_stderr(self.inner(), bufferPtr, bufferLength)
+	}
+
+	runtime.KeepAlive(self)
 
-	return *(*[]byte)(unsafe.Pointer(&header))
+	if length < 0 {
+		return 0, newErrorWith("Cannot read the captured " + stream.String() + ", is it captured?")
+	}
+
+	if length == 0 {
+		return 0, io.EOF
+	}
+
+	return int(length), nil
+}
+
+func (self *WasiEnvironment) readAll(stream wasiStream) []byte {
+	bytes, _ := io.ReadAll(&wasiStreamReader{environment: self, stream: stream})
+
+	return bytes
+}
+
+// wasiStreamReader is an io.Reader over a captured stream.
+type wasiStreamReader struct {
+	environment *WasiEnvironment
+	stream      wasiStream
+}
+
+func (self *wasiStreamReader) Read(buffer []byte) (int, error) {
+	return self.environment.read(self.stream, buffer)
 }
 
 // ReadStdout reads the WASI module stdout if captured with
@@ -322,21 +396,100 @@ func buildByteSliceFromCBuffer(buffer *C.char, length int) []byte {
 //  start()
 //
 //  stdout := string(wasiEnv.ReadStdout())
-//func (self *WasiEnvironment) ReadStdout() []byte {
-//	var buffer *C.char
-//	length := int(C.to_wasi_env_read_stdout(self.inner(), &buffer))
-//
-//	return buildByteSliceFromCBuffer(buffer, length)
-//}
+func (self *WasiEnvironment) ReadStdout() []byte {
+	return self.readAll(wasiStdout)
+}
 
 // ReadStderr reads the WASI module stderr if captured with
 // WasiStateBuilder.CaptureStderr. See ReadStdout to see an example.
-//func (self *WasiEnvironment) ReadStderr() []byte {
-//	var buffer *C.char
-//	length := int(C.to_wasi_env_read_stderr(self.inner(), &buffer))
+func (self *WasiEnvironment) ReadStderr() []byte {
+	return self.readAll(wasiStderr)
+}
+
+// Stdout returns an io.Reader over the WASI module stdout if captured
+// with WasiStateBuilder.CaptureStdout. Read returns io.EOF when all
+// the output so far is read, and more once the module writes again.
+//
+//  start()
+//
+//  scanner := bufio.NewScanner(wasiEnv.Stdout())
+//  for scanner.Scan() {
+//  	fmt.Println(scanner.Text())
+//  }
+func (self *WasiEnvironment) Stdout() io.Reader {
+	return &wasiStreamReader{environment: self, stream: wasiStdout}
+}
+
+// Stderr returns an io.Reader over the WASI module stderr if captured
+// with WasiStateBuilder.CaptureStderr. See Stdout.
+func (self *WasiEnvironment) Stderr() io.Reader {
+	return &wasiStreamReader{environment: self, stream: wasiStderr}
+}
+
+// Flush copies the captured stdout and stderr to the writers given to
+// WasiStateBuilder.CaptureStdoutTo and CaptureStderrTo. Call it from
+// time to time while the module runs; the writers aren't closed, so a
+// LogWriter keeps a last line without a line ending, see CloseWriters.
+//
+//  start()
+//
+//  err := wasiEnv.Flush()
+func (self *WasiEnvironment) Flush() error {
+	if self.stdoutWriter != nil {
+		if _, err := io.Copy(self.stdoutWriter, self.Stdout()); err != nil {
+			return err
+		}
+	}
+
+	if self.stderrWriter != nil {
+		if _, err := io.Copy(self.stderrWriter, self.Stderr()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// CloseWriters flushes the captured stdout and stderr, then closes
+// the writers given to WasiStateBuilder.CaptureStdoutTo and
+// CaptureStderrTo that implement io.Closer, so a LogWriter prints its
+// last line even without a line ending. Call it once the module
+// returns instead of Flush.
 //
-//	return buildByteSliceFromCBuffer(buffer, length)
-//}
+//  start()
+//
+//  err := wasiEnv.CloseWriters()
+func (self *WasiEnvironment) CloseWriters() error {
+	err := self.Flush()
+
+	if closeErr := self.closeWriters(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// closeWriters closes the writers of the environment, see
+// closeWriters.
+func (self *WasiEnvironment) closeWriters() error {
+	return closeWriters(self.stdoutWriter, self.stderrWriter)
+}
+
+// closeWriters closes the writers that implement io.Closer, and
+// returns the first Error.
+func closeWriters(writers ...io.Writer) error {
+	var err error
+
+	for _, writer := range writers {
+		if closer, ok := writer.(io.Closer); ok {
+			if closeErr := closer.Close(); closeErr != nil && err == nil {
+				err = closeErr
+			}
+		}
+	}
+
+	return err
+}
 
 // GenerateImportObject generates an import object, that can be
 // extended and passed to NewInstance.
@@ -390,5 +543,11 @@ func (self *WasiEnvironment) GenerateImportObject(store *Store, module *Module)
 
 	C.wasmer_named_extern_vec_delete(&wasiNamedExterns)
 
+	if self.sandbox != nil {
+		if err := self.sandbox.register(store, module, importObject); err != nil {
+			return nil, err
+		}
+	}
+
 	return importObject, nil
 }
@@ -0,0 +1,459 @@
+package wasmer
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errNotDir   = errors.New("not a directory")
+	errIsDir    = errors.New("is a directory")
+	errNotEmpty = errors.New("directory not empty")
+)
+
+// MemoryFS is an in-memory tree of directories and files. It
+// implements fs.FS, so it can be read with the io/fs helpers, and it
+// can be mounted read-write in a WasiSandbox, unlike other fs.FS.
+//
+//   memoryFS := NewMemoryFS()
+//   _ = memoryFS.WriteFile("etc/config.json", []byte(`{}`))
+//   sandbox := NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE)
+//
+// Names are slash-separated, unrooted paths, see fs.ValidPath. Every
+// modification time is the zero time, so a listing never depends on
+// when the files were written.
+type MemoryFS struct {
+	lock sync.RWMutex
+	root *memoryNode
+	// The sum of the sizes of every file.
+	size int64
+}
+
+type memoryNode struct {
+	name     string
+	dir      bool
+	data     []byte
+	children map[string]*memoryNode
+	// Set once the node is removed while a file of a WasiSandbox may
+	// still have it open: its size isn't counted any more.
+	removed bool
+}
+
+// NewMemoryFS creates a new empty MemoryFS.
+func NewMemoryFS() *MemoryFS {
+	return &MemoryFS{
+		root: &memoryNode{name: ".", dir: true, children: make(map[string]*memoryNode)},
+	}
+}
+
+// WriteFile writes data to the named file, creating it and its parent
+// directories if they don't exist.
+func (self *MemoryFS) WriteFile(name string, data []byte) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err := self.mkdirAll(path.Dir(name)); err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+
+	node, err := self.create(name, false)
+
+	if err != nil {
+		return &fs.PathError{Op: "write", Path: name, Err: err}
+	}
+
+	self.size += int64(len(data)) - int64(len(node.data))
+	node.data = append([]byte(nil), data...)
+
+	return nil
+}
+
+// MkdirAll creates the named directory and its parent directories if
+// they don't exist.
+func (self *MemoryFS) MkdirAll(name string) error {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if err := self.mkdirAll(name); err != nil {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+// Size returns the sum of the sizes of every file.
+func (self *MemoryFS) Size() int64 {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	return self.size
+}
+
+// Open implements fs.FS. The content of an opened file is a copy, it
+// doesn't change when the file is written.
+func (self *MemoryFS) Open(name string) (fs.File, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	node, err := self.lookup(name)
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if node.dir {
+		return &memoryDir{info: node.info(), entries: node.entries()}, nil
+	}
+
+	return &memoryFile{info: node.info(), Reader: bytes.NewReader(append([]byte(nil), node.data...))}, nil
+}
+
+// ReadFile implements fs.ReadFileFS.
+func (self *MemoryFS) ReadFile(name string) ([]byte, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	node, err := self.lookup(name)
+
+	if err == nil && node.dir {
+		err = errIsDir
+	}
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "read", Path: name, Err: err}
+	}
+
+	return append([]byte(nil), node.data...), nil
+}
+
+// ReadDir implements fs.ReadDirFS.
+func (self *MemoryFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	node, err := self.lookup(name)
+
+	if err == nil && !node.dir {
+		err = errNotDir
+	}
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	return node.entries(), nil
+}
+
+// Stat implements fs.StatFS.
+func (self *MemoryFS) Stat(name string) (fs.FileInfo, error) {
+	self.lock.RLock()
+	defer self.lock.RUnlock()
+
+	node, err := self.lookup(name)
+
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	return node.info(), nil
+}
+
+// lookup returns the named node, the lock must be held.
+func (self *MemoryFS) lookup(name string) (*memoryNode, error) {
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrInvalid
+	}
+
+	node := self.root
+
+	if name == "." {
+		return node, nil
+	}
+
+	for _, element := range strings.Split(name, "/") {
+		if !node.dir {
+			return nil, errNotDir
+		}
+
+		child, exists := node.children[element]
+
+		if !exists {
+			return nil, fs.ErrNotExist
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+// parent returns the directory the named node is in and the base
+// name of the node, the lock must be held.
+func (self *MemoryFS) parent(name string) (*memoryNode, string, error) {
+	if !fs.ValidPath(name) || name == "." {
+		return nil, "", fs.ErrInvalid
+	}
+
+	parent, err := self.lookup(path.Dir(name))
+
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !parent.dir {
+		return nil, "", errNotDir
+	}
+
+	return parent, path.Base(name), nil
+}
+
+// create returns the named node, created if it doesn't exist, the
+// lock must be held.
+func (self *MemoryFS) create(name string, dir bool) (*memoryNode, error) {
+	parent, base, err := self.parent(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if node, exists := parent.children[base]; exists {
+		if node.dir != dir {
+			if node.dir {
+				return nil, errIsDir
+			}
+
+			return nil, errNotDir
+		}
+
+		return node, nil
+	}
+
+	node := &memoryNode{name: base, dir: dir}
+
+	if dir {
+		node.children = make(map[string]*memoryNode)
+	}
+
+	parent.children[base] = node
+
+	return node, nil
+}
+
+// mkdirAll creates the named directory and its parents, the lock must
+// be held.
+func (self *MemoryFS) mkdirAll(name string) error {
+	if !fs.ValidPath(name) {
+		return fs.ErrInvalid
+	}
+
+	if name == "." {
+		return nil
+	}
+
+	current := "."
+
+	for _, element := range strings.Split(name, "/") {
+		current = path.Join(current, element)
+
+		if _, err := self.create(current, true); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// remove removes the named file, or the named empty directory if dir,
+// the lock must be held.
+func (self *MemoryFS) remove(name string, dir bool) error {
+	parent, base, err := self.parent(name)
+
+	if err != nil {
+		return err
+	}
+
+	node, exists := parent.children[base]
+
+	if !exists {
+		return fs.ErrNotExist
+	}
+
+	if dir && !node.dir {
+		return errNotDir
+	}
+
+	if !dir && node.dir {
+		return errIsDir
+	}
+
+	if dir && len(node.children) > 0 {
+		return errNotEmpty
+	}
+
+	self.size -= int64(len(node.data))
+	node.removed = true
+	delete(parent.children, base)
+
+	return nil
+}
+
+// rename moves the node at oldName to newName, replacing the file
+// or empty directory there, the lock must be held.
+func (self *MemoryFS) rename(oldName string, newName string) error {
+	oldParent, oldBase, err := self.parent(oldName)
+
+	if err != nil {
+		return err
+	}
+
+	node, exists := oldParent.children[oldBase]
+
+	if !exists {
+		return fs.ErrNotExist
+	}
+
+	if oldName == newName {
+		return nil
+	}
+
+	newParent, newBase, err := self.parent(newName)
+
+	if err != nil {
+		return err
+	}
+
+	if node.dir && strings.HasPrefix(newName+"/", oldName+"/") {
+		// A directory can't be moved into itself.
+		return fs.ErrInvalid
+	}
+
+	if target, exists := newParent.children[newBase]; exists && target != node {
+		if target.dir && !node.dir {
+			return errIsDir
+		}
+
+		if !target.dir && node.dir {
+			return errNotDir
+		}
+
+		if err := self.remove(newName, target.dir); err != nil {
+			return err
+		}
+	}
+
+	delete(oldParent.children, oldBase)
+	node.name = newBase
+	newParent.children[newBase] = node
+
+	return nil
+}
+
+// resize sets the size of the file, zero-filled if it grows, the
+// lock must be held.
+func (self *MemoryFS) resize(node *memoryNode, size int64) {
+	if !node.removed {
+		self.size += size - int64(len(node.data))
+	}
+
+	if size <= int64(len(node.data)) {
+		node.data = node.data[:size]
+	} else {
+		node.data = append(node.data, make([]byte, size-int64(len(node.data)))...)
+	}
+}
+
+func (self *memoryNode) info() *memoryFileInfo {
+	return &memoryFileInfo{name: self.name, dir: self.dir, size: int64(len(self.data))}
+}
+
+func (self *memoryNode) entries() []fs.DirEntry {
+	names := make([]string, 0, len(self.children))
+
+	for name := range self.children {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	entries := make([]fs.DirEntry, 0, len(names))
+
+	for _, name := range names {
+		entries = append(entries, self.children[name].info())
+	}
+
+	return entries
+}
+
+// memoryFileInfo implements fs.FileInfo and fs.DirEntry.
+type memoryFileInfo struct {
+	name string
+	dir  bool
+	size int64
+}
+
+func (self *memoryFileInfo) Name() string { return self.name }
+func (self *memoryFileInfo) Size() int64  { return self.size }
+func (self *memoryFileInfo) Mode() fs.FileMode {
+	if self.dir {
+		return fs.ModeDir | 0755
+	}
+
+	return 0644
+}
+func (self *memoryFileInfo) ModTime() time.Time         { return time.Time{} }
+func (self *memoryFileInfo) IsDir() bool                { return self.dir }
+func (self *memoryFileInfo) Sys() interface{}           { return nil }
+func (self *memoryFileInfo) Type() fs.FileMode          { return self.Mode().Type() }
+func (self *memoryFileInfo) Info() (fs.FileInfo, error) { return self, nil }
+
+// memoryFile is a file opened with MemoryFS.Open.
+type memoryFile struct {
+	*bytes.Reader
+	info *memoryFileInfo
+}
+
+func (self *memoryFile) Stat() (fs.FileInfo, error) { return self.info, nil }
+func (self *memoryFile) Close() error               { return nil }
+
+// memoryDir is a directory opened with MemoryFS.Open.
+type memoryDir struct {
+	info    *memoryFileInfo
+	entries []fs.DirEntry
+}
+
+func (self *memoryDir) Stat() (fs.FileInfo, error) { return self.info, nil }
+func (self *memoryDir) Close() error               { return nil }
+
+func (self *memoryDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: self.info.name, Err: errIsDir}
+}
+
+// ReadDir implements fs.ReadDirFile.
+func (self *memoryDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if count <= 0 {
+		entries := self.entries
+		self.entries = nil
+
+		return entries, nil
+	}
+
+	if len(self.entries) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > len(self.entries) {
+		count = len(self.entries)
+	}
+
+	entries := self.entries[:count]
+	self.entries = self.entries[count:]
+
+	return entries, nil
+}
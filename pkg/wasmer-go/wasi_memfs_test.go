@@ -0,0 +1,53 @@
+package wasmer
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryFS(t *testing.T) {
+	memoryFS := NewMemoryFS()
+	assert.NoError(t, memoryFS.WriteFile("a/b/c.txt", []byte("hello")))
+	assert.NoError(t, memoryFS.WriteFile("d.txt", []byte("world!")))
+	assert.NoError(t, memoryFS.MkdirAll("e/f"))
+
+	assert.NoError(t, fstest.TestFS(memoryFS, "a/b/c.txt", "d.txt", "e/f"))
+	assert.Equal(t, int64(11), memoryFS.Size())
+
+	data, err := fs.ReadFile(memoryFS, "a/b/c.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	// a file is not a directory
+	assert.Error(t, memoryFS.WriteFile("d.txt/g.txt", nil))
+	_, err = memoryFS.ReadFile("a")
+	assert.ErrorIs(t, err, errIsDir)
+	_, err = memoryFS.Stat("x")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
+func TestMemoryFSChanges(t *testing.T) {
+	memoryFS := NewMemoryFS()
+	assert.NoError(t, memoryFS.WriteFile("a/b.txt", []byte("hello")))
+	assert.NoError(t, memoryFS.MkdirAll("c"))
+
+	assert.ErrorIs(t, memoryFS.remove("a", true), errNotEmpty)
+	assert.ErrorIs(t, memoryFS.rename("a", "a/d"), fs.ErrInvalid)
+	assert.ErrorIs(t, memoryFS.rename("a/b.txt", "c"), errIsDir)
+
+	assert.NoError(t, memoryFS.rename("a/b.txt", "c/b.txt"))
+	assert.NoError(t, memoryFS.remove("a", true))
+	assert.Equal(t, int64(5), memoryFS.Size())
+
+	// a file removed while open isn't counted when written
+	node, err := memoryFS.lookup("c/b.txt")
+	assert.NoError(t, err)
+	assert.NoError(t, memoryFS.remove("c/b.txt", false))
+	assert.Equal(t, int64(0), memoryFS.Size())
+	memoryFS.resize(node, 10)
+	assert.Equal(t, int64(0), memoryFS.Size())
+	assert.NoError(t, fstest.TestFS(memoryFS, "c"))
+}
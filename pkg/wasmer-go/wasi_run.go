@@ -0,0 +1,111 @@
+package wasmer
+
+import (
+	"fmt"
+)
+
+// ExitError is returned by RunWasi when the WASI module calls
+// proc_exit with a non-zero code.
+type ExitError struct {
+	Code uint32
+}
+
+// Error returns the exit code as a message.
+func (self *ExitError) Error() string {
+	return fmt.Sprintf("WASI module exited with code %d", self.Code)
+}
+
+// RunWasi runs a WASI command module like a CLI program: it
+// instantiates module with the WASI environment of builder, calls
+// `_start`, and returns what the module wrote to its stdout and
+// stderr, which are captured. The writers given to
+// WasiStateBuilder.CaptureStdoutTo and CaptureStderrTo get a copy,
+// then are closed if they implement io.Closer, even if RunWasi fails.
+//
+// A proc_exit(0) is a success, and a proc_exit with another code
+// returns an ExitError; any other trap is returned as is.
+//
+//   stdout, _, err := wasmer.RunWasi(module, wasmer.NewWasiStateBuilder("hello").Argument("--foo"))
+//
+//   var exitError *wasmer.ExitError
+//   if errors.As(err, &exitError) {
+//       os.Exit(int(exitError.Code))
+//   }
+func RunWasi(module *Module, builder *WasiStateBuilder) (stdout []byte, stderr []byte, err error) {
+	wasiEnv, err := builder.CaptureStdout().CaptureStderr().Finalize()
+
+	if err != nil {
+		_ = closeWriters(builder.stdoutWriter, builder.stderrWriter)
+
+		return nil, nil, err
+	}
+
+	defer func() {
+		if closeErr := wasiEnv.closeWriters(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	importObject, err := wasiEnv.GenerateImportObject(module.store, module)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// proc_exit unwinds the module with a trap, exit tells it apart
+	// from any other trap.
+	var exit *ExitError
+
+	namespace := GetWasiVersion(module).String()
+
+	if _, exists := importObject.externs[namespace]["proc_exit"]; exists {
+		importObject.externs[namespace]["proc_exit"] = NewFunction(
+			module.store,
+			NewFunctionType(NewValueTypes(I32), NewValueTypes()),
+			func(args []Value) ([]Value, error) {
+				exit = &ExitError{Code: uint32(args[0].I32())}
+
+				return nil, exit
+			},
+		)
+	}
+
+	instance, err := NewInstance(module, importObject)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	defer instance.Close()
+
+	start, err := instance.Exports.GetWasiStartFunction()
+
+	if err == nil {
+		_, err = start()
+	}
+
+	if exit != nil {
+		err = nil
+
+		if exit.Code != 0 {
+			err = exit
+		}
+	}
+
+	stdout = wasiEnv.ReadStdout()
+	stderr = wasiEnv.ReadStderr()
+
+	if wasiEnv.stdoutWriter != nil {
+		if _, writeErr := wasiEnv.stdoutWriter.Write(stdout); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	if wasiEnv.stderrWriter != nil {
+		if _, writeErr := wasiEnv.stderrWriter.Write(stderr); writeErr != nil && err == nil {
+			err = writeErr
+		}
+	}
+
+	return stdout, stderr, err
+}
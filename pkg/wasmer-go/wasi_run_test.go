@@ -0,0 +1,107 @@
+package wasmer
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wasiCommandWat writes hello to its stdout and exits with the code
+// at 16.
+const wasiCommandWat = `(module
+	(import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+	(memory (export "memory") 1)
+	(data (i32.const 0) "\08\00\00\00\06\00\00\00hello\n")
+	(data (i32.const 16) "\03\00\00\00")
+	(func (export "_start")
+		(drop (call $fd_write (i32.const 1) (i32.const 0) (i32.const 1) (i32.const 20)))
+		(call $proc_exit (i32.load (i32.const 16)))
+		unreachable))`
+
+func TestRunWasi(t *testing.T) {
+	store := NewStore(NewEngine())
+	module, err := NewModuleFromWat(store, wasiCommandWat, nil)
+	assert.NoError(t, err)
+
+	var copied bytes.Buffer
+	stdout, stderr, err := RunWasi(module, NewWasiStateBuilder("test-program").CaptureStdoutTo(&copied))
+	assert.Equal(t, &ExitError{Code: 3}, err)
+	assert.Equal(t, "hello\n", string(stdout))
+	assert.Empty(t, stderr)
+	assert.Equal(t, "hello\n", copied.String())
+
+	stdout, _, err = RunWasi(module, NewWasiStateBuilder("test-program").Sandbox(NewWasiSandbox()))
+	assert.Equal(t, &ExitError{Code: 3}, err)
+	assert.Equal(t, "hello\n", string(stdout))
+}
+
+func TestRunWasiExitZero(t *testing.T) {
+	store := NewStore(NewEngine())
+	module, err := NewModuleFromWat(store, `(module
+	(import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+	(memory (export "memory") 1)
+	(func (export "_start")
+		(call $proc_exit (i32.const 0))
+		unreachable))`, nil)
+	assert.NoError(t, err)
+
+	_, _, err = RunWasi(module, NewWasiStateBuilder("test-program"))
+	assert.NoError(t, err)
+}
+
+func TestRunWasiTrap(t *testing.T) {
+	store := NewStore(NewEngine())
+	module, err := NewModuleFromWat(store, `(module
+	(import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+	(memory (export "memory") 1)
+	(func (export "_start") unreachable))`, nil)
+	assert.NoError(t, err)
+
+	_, _, err = RunWasi(module, NewWasiStateBuilder("test-program"))
+	assert.Error(t, err)
+	_, isExit := err.(*ExitError)
+	assert.False(t, isExit)
+	assert.Equal(t, "_start", err.(*TrapError).Origin().FunctionName())
+}
+
+// closingBuffer records whether it is closed.
+type closingBuffer struct {
+	bytes.Buffer
+	closed bool
+}
+
+func (self *closingBuffer) Close() error {
+	self.closed = true
+
+	return nil
+}
+
+func TestRunWasiClosesWriters(t *testing.T) {
+	store := NewStore(NewEngine())
+	module, err := NewModuleFromWat(store, wasiCommandWat, nil)
+	assert.NoError(t, err)
+
+	// Finalize fails
+	var stdout closingBuffer
+	_, _, err = RunWasi(module, NewWasiStateBuilder("test-program").
+		PreopenDirectory(".").
+		Sandbox(NewWasiSandbox()).
+		CaptureStdoutTo(&stdout))
+	assert.Error(t, err)
+	assert.True(t, stdout.closed)
+
+	// the module can't be instantiated
+	module, err = NewModuleFromWat(store, `(module
+	(import "wasi_snapshot_preview1" "proc_exit" (func $proc_exit (param i32)))
+	(import "env" "missing" (func $missing))
+	(memory (export "memory") 1)
+	(func (export "_start")))`, nil)
+	assert.NoError(t, err)
+
+	stdout = closingBuffer{}
+	_, _, err = RunWasi(module, NewWasiStateBuilder("test-program").CaptureStdoutTo(&stdout))
+	assert.Error(t, err)
+	assert.True(t, stdout.closed)
+}
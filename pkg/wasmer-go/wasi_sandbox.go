@@ -0,0 +1,1490 @@
+package wasmer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math/rand"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+var errCrossMount = errors.New("cannot rename across mounts")
+
+// WasiAccess is the access a WasiSandbox gives to a mounted
+// filesystem.
+type WasiAccess int
+
+const (
+	// The module can read the files but not change them.
+	WASI_READ_ONLY WasiAccess = iota
+
+	// The module can create, write, rename and remove the files. Only
+	// a MemoryFS can be mounted read-write.
+	WASI_READ_WRITE
+)
+
+// WasiSandbox is the policy of a WASI module that must not touch the
+// host: the filesystems it sees, the quotas on what it writes, and
+// the clock and random sources it reads. Nothing depends on the host,
+// so two runs of the same module with the same sandbox behave the
+// same.
+//
+//   memoryFS := NewMemoryFS()
+//   sandbox := NewWasiSandbox().
+//       Mount("/data", os.DirFS("testdata"), WASI_READ_ONLY).
+//       Mount("/tmp", memoryFS, WASI_READ_WRITE).
+//       MaxTotalSize(1 << 20).
+//       RandomSeed(42)
+//   wasiEnv, _ := NewWasiStateBuilder("test-program").
+//       Sandbox(sandbox).
+//       Finalize()
+//
+// The sandbox implements the filesystem, clock, poll, random and stdio
+// functions of wasi_snapshot_preview1 in Go, and refuses the socket
+// ones. Finalize fails if WasiStateBuilder.PreopenDirectory or
+// MapDirectory is used too, and stdout and stderr are always captured.
+type WasiSandbox struct {
+	mounts       []*wasiMount
+	maxFileSize  int64
+	maxTotalSize int64
+	maxOpenFiles int
+	clockStart   time.Time
+	clockStep    time.Duration
+	randomSeed   int64
+	random       io.Reader
+	stdin        io.Reader
+	// The first invalid setting, returned by Finalize.
+	err error
+}
+
+type wasiMount struct {
+	guestPath string
+	fsys      fs.FS
+	access    WasiAccess
+	// The filesystem of a read-write mount.
+	memoryFS *MemoryFS
+}
+
+// NewWasiSandbox creates a new WasiSandbox with no mounts, no size
+// quotas, at most 64 open files, a clock starting at 2000-01-01 UTC
+// and random bytes seeded with 0.
+//
+//   sandbox := NewWasiSandbox()
+func NewWasiSandbox() *WasiSandbox {
+	return &WasiSandbox{
+		maxOpenFiles: 64,
+		clockStart:   time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC),
+		clockStep:    time.Millisecond,
+	}
+}
+
+// Mount makes fsys visible to the module at guestPath, a pre-opened
+// directory. The mounts are pre-opened in the order they are added.
+//
+//   sandbox := NewWasiSandbox().
+//       Mount("/", fstest.MapFS{"hello.txt": {Data: []byte("hello")}}, WASI_READ_ONLY)
+func (self *WasiSandbox) Mount(guestPath string, fsys fs.FS, access WasiAccess) *WasiSandbox {
+	mount := &wasiMount{guestPath: path.Clean(guestPath), fsys: fsys, access: access}
+
+	if access == WASI_READ_WRITE {
+		memoryFS, ok := fsys.(*MemoryFS)
+
+		if !ok && self.err == nil {
+			self.err = newErrorWith(fmt.Sprintf("Cannot mount `%s` read-write, it is not a MemoryFS", guestPath))
+		}
+
+		mount.memoryFS = memoryFS
+	}
+
+	self.mounts = append(self.mounts, mount)
+
+	return self
+}
+
+// MaxFileSize limits the size of a file written by the module, a
+// write past it fails with EFBIG. 0 means no limit.
+func (self *WasiSandbox) MaxFileSize(size int64) *WasiSandbox {
+	self.maxFileSize = size
+
+	return self
+}
+
+// MaxTotalSize limits the total size of the files in each read-write
+// mount, a write past it fails with ENOSPC. 0 means no limit. A file
+// unlinked while open isn't counted any more, only MaxFileSize limits
+// it until it is closed.
+func (self *WasiSandbox) MaxTotalSize(size int64) *WasiSandbox {
+	self.maxTotalSize = size
+
+	return self
+}
+
+// MaxOpenFiles limits the number of files and directories the module
+// has open at once, besides stdio and the mounts; opening one more
+// fails with EMFILE. 0 means no limit.
+func (self *WasiSandbox) MaxOpenFiles(count int) *WasiSandbox {
+	self.maxOpenFiles = count
+
+	return self
+}
+
+// Clock sets the time the module reads: start, then start plus step
+// at the next read, and so on. Every clock of the module is this one.
+//
+//   sandbox := NewWasiSandbox().
+//       Clock(time.Unix(1600000000, 0), time.Second)
+func (self *WasiSandbox) Clock(start time.Time, step time.Duration) *WasiSandbox {
+	self.clockStart = start
+	self.clockStep = step
+
+	return self
+}
+
+// RandomSeed seeds the pseudo-random bytes the module reads; every
+// environment of the sandbox reads the same bytes.
+func (self *WasiSandbox) RandomSeed(seed int64) *WasiSandbox {
+	self.randomSeed = seed
+	self.random = nil
+
+	return self
+}
+
+// Random sets where the module reads random bytes from, e.g.
+// crypto/rand.Reader. The reader is shared by every environment of
+// the sandbox.
+func (self *WasiSandbox) Random(source io.Reader) *WasiSandbox {
+	self.random = source
+
+	return self
+}
+
+// Stdin sets what the module reads on its stdin, which is empty
+// otherwise.
+func (self *WasiSandbox) Stdin(reader io.Reader) *WasiSandbox {
+	self.stdin = reader
+
+	return self
+}
+
+func (self *WasiSandbox) newState() (*wasiSandboxState, error) {
+	if self.err != nil {
+		return nil, self.err
+	}
+
+	state := &wasiSandboxState{
+		sandbox: self,
+		files:   make(map[uint32]*wasiFile),
+		random:  self.random,
+	}
+
+	if state.random == nil {
+		state.random = rand.New(rand.NewSource(self.randomSeed))
+	}
+
+	stdin := self.stdin
+
+	if stdin == nil {
+		stdin = bytes.NewReader(nil)
+	}
+
+	state.files[0] = &wasiFile{reader: stdin}
+	state.files[1] = &wasiFile{writer: &state.stdout}
+	state.files[2] = &wasiFile{writer: &state.stderr}
+
+	for nth, mount := range self.mounts {
+		state.files[uint32(3+nth)] = &wasiFile{mount: mount, name: ".", dir: true, preopen: true}
+	}
+
+	return state, nil
+}
+
+// wasiSandboxState is the state of one WasiEnvironment with a sandbox.
+type wasiSandboxState struct {
+	// Held during every call of a sandbox function, and by read.
+	lock    sync.Mutex
+	sandbox *WasiSandbox
+	memory  *Memory
+	files   map[uint32]*wasiFile
+	// The number of clock reads so far.
+	ticks  int64
+	random io.Reader
+	stdout bytes.Buffer
+	stderr bytes.Buffer
+}
+
+// wasiFile is an open file descriptor.
+type wasiFile struct {
+	mount *wasiMount
+	// The path of the file in the filesystem of the mount.
+	name    string
+	dir     bool
+	preopen bool
+	// The file of a read-write mount.
+	node *memoryNode
+	// The content of a file of a read-only mount, read when opened.
+	data     []byte
+	offset   int64
+	append   bool
+	writable bool
+	// stdin, or stdout and stderr.
+	reader io.Reader
+	writer io.Writer
+}
+
+func (self *wasiFile) stdio() bool {
+	return self.reader != nil || self.writer != nil
+}
+
+func (self *wasiFile) size() int64 {
+	if self.node != nil {
+		self.mount.memoryFS.lock.RLock()
+		defer self.mount.memoryFS.lock.RUnlock()
+
+		return int64(len(self.node.data))
+	}
+
+	return int64(len(self.data))
+}
+
+// readAt copies the content at offset to p.
+func (self *wasiFile) readAt(p []byte, offset int64) int {
+	data := self.data
+
+	if self.node != nil {
+		self.mount.memoryFS.lock.RLock()
+		defer self.mount.memoryFS.lock.RUnlock()
+
+		data = self.node.data
+	}
+
+	if offset >= int64(len(data)) {
+		return 0
+	}
+
+	return copy(p, data[offset:])
+}
+
+// read moves at most len(buffer) bytes of the captured stream into
+// buffer; it returns io.EOF once nothing is left.
+func (self *wasiSandboxState) read(stream wasiStream, buffer []byte) (int, error) {
+	self.lock.Lock()
+	defer self.lock.Unlock()
+
+	if len(buffer) == 0 {
+		return 0, nil
+	}
+
+	if stream == wasiStdout {
+		return self.stdout.Read(buffer)
+	}
+
+	return self.stderr.Read(buffer)
+}
+
+// register replaces the WASI functions the sandbox implements in
+// importObject, and binds the sandbox to the memory of the instance
+// once it is created.
+func (self *wasiSandboxState) register(store *Store, module *Module, importObject *ImportObject) error {
+	if version := GetWasiVersion(module); version != WASI_VERSION_SNAPSHOT1 {
+		return newErrorWith(fmt.Sprintf("A WASI sandbox only runs `%s` modules, not `%s`", WASI_VERSION_SNAPSHOT1, version))
+	}
+
+	namespace := WASI_VERSION_SNAPSHOT1.String()
+
+	if _, exists := importObject.externs[namespace]; !exists {
+		importObject.externs[namespace] = make(map[string]IntoExtern)
+	}
+
+	for _, importType := range module.Imports() {
+		if importType.Module() != namespace {
+			continue
+		}
+
+		definition, exists := wasiSandboxFunctions[importType.Name()]
+
+		if !exists {
+			continue
+		}
+
+		importObject.externs[namespace][importType.Name()] = self.newFunction(store, definition)
+	}
+
+	importObject.instantiated = append(importObject.instantiated, self.bind)
+
+	return nil
+}
+
+func (self *wasiSandboxState) newFunction(store *Store, definition wasiSandboxFunction) *Function {
+	return NewFunctionWithEnvironment(
+		store,
+		NewFunctionType(NewValueTypes(definition.params...), NewValueTypes(I32)),
+		self,
+		func(environment interface{}, args []Value) ([]Value, error) {
+			state := environment.(*wasiSandboxState)
+
+			state.lock.Lock()
+			defer state.lock.Unlock()
+
+			if state.memory == nil {
+				return nil, newErrorWith("The WASI sandbox is not bound to an instance")
+			}
+
+			return []Value{NewI32(int32(definition.call(state, args)))}, nil
+		},
+	)
+}
+
+func (self *wasiSandboxState) bind(instance *Instance) error {
+	memory, err := instance.Exports.GetMemory("memory")
+
+	if err != nil {
+		return newErrorWith("A WASI sandbox needs the module to export its memory as `memory`")
+	}
+
+	self.lock.Lock()
+	self.memory = memory
+	self.lock.Unlock()
+
+	return nil
+}
+
+// wasiErrno is an errno of wasi_snapshot_preview1.
+type wasiErrno int32
+
+const (
+	wasiErrnoSuccess     wasiErrno = 0
+	wasiErrnoAcces       wasiErrno = 2
+	wasiErrnoBadf        wasiErrno = 8
+	wasiErrnoExist       wasiErrno = 20
+	wasiErrnoFault       wasiErrno = 21
+	wasiErrnoFbig        wasiErrno = 22
+	wasiErrnoInval       wasiErrno = 28
+	wasiErrnoIo          wasiErrno = 29
+	wasiErrnoIsdir       wasiErrno = 31
+	wasiErrnoMfile       wasiErrno = 33
+	wasiErrnoNametoolong wasiErrno = 37
+	wasiErrnoNoent       wasiErrno = 44
+	wasiErrnoNospc       wasiErrno = 51
+	wasiErrnoNotdir      wasiErrno = 54
+	wasiErrnoNotempty    wasiErrno = 55
+	wasiErrnoNotsup      wasiErrno = 58
+	wasiErrnoRofs        wasiErrno = 69
+	wasiErrnoSpipe       wasiErrno = 70
+	wasiErrnoXdev        wasiErrno = 75
+	wasiErrnoNotcapable  wasiErrno = 76
+)
+
+func wasiErrnoOf(err error) wasiErrno {
+	switch {
+	case err == nil:
+		return wasiErrnoSuccess
+	case errors.Is(err, fs.ErrNotExist):
+		return wasiErrnoNoent
+	case errors.Is(err, fs.ErrExist):
+		return wasiErrnoExist
+	case errors.Is(err, fs.ErrPermission):
+		return wasiErrnoAcces
+	case errors.Is(err, fs.ErrInvalid):
+		return wasiErrnoInval
+	case errors.Is(err, errNotDir):
+		return wasiErrnoNotdir
+	case errors.Is(err, errIsDir):
+		return wasiErrnoIsdir
+	case errors.Is(err, errNotEmpty):
+		return wasiErrnoNotempty
+	case errors.Is(err, errCrossMount):
+		return wasiErrnoXdev
+	default:
+		return wasiErrnoIo
+	}
+}
+
+const (
+	wasiFiletypeCharacterDevice uint8 = 2
+	wasiFiletypeDirectory       uint8 = 3
+	wasiFiletypeRegularFile     uint8 = 4
+	wasiFiletypeSymbolicLink    uint8 = 7
+
+	wasiOflagsCreat     = 1
+	wasiOflagsDirectory = 2
+	wasiOflagsExcl      = 4
+	wasiOflagsTrunc     = 8
+
+	wasiFdflagsAppend = 1
+
+	wasiRightsFdWrite uint64 = 1 << 6
+	wasiRightsAll     uint64 = 1<<29 - 1
+
+	wasiMaxPathLength = 4096
+
+	wasiEventtypeClock   = 0
+	wasiEventtypeFdRead  = 1
+	wasiEventtypeFdWrite = 2
+
+	wasiSubclockflagsAbstime = 1
+
+	wasiSubscriptionSize = 48
+	wasiEventSize        = 32
+)
+
+type wasiSandboxFunction struct {
+	params []ValueKind
+	call   func(*wasiSandboxState, []Value) wasiErrno
+}
+
+// wasiSandboxFunctions are the functions of wasi_snapshot_preview1 a
+// sandbox implements; the arguments, environment, proc_exit and
+// sched_yield functions are left to Wasmer. The module has no
+// sockets.
+var wasiSandboxFunctions = map[string]wasiSandboxFunction{
+	"clock_res_get":           {[]ValueKind{I32, I32}, (*wasiSandboxState).clockResGet},
+	"clock_time_get":          {[]ValueKind{I32, I64, I32}, (*wasiSandboxState).clockTimeGet},
+	"fd_advise":               {[]ValueKind{I32, I64, I64, I32}, (*wasiSandboxState).fdNoop},
+	"fd_allocate":             {[]ValueKind{I32, I64, I64}, (*wasiSandboxState).notSupported},
+	"fd_close":                {[]ValueKind{I32}, (*wasiSandboxState).fdClose},
+	"fd_datasync":             {[]ValueKind{I32}, (*wasiSandboxState).fdNoop},
+	"fd_fdstat_get":           {[]ValueKind{I32, I32}, (*wasiSandboxState).fdFdstatGet},
+	"fd_fdstat_set_flags":     {[]ValueKind{I32, I32}, (*wasiSandboxState).fdFdstatSetFlags},
+	"fd_fdstat_set_rights":    {[]ValueKind{I32, I64, I64}, (*wasiSandboxState).fdNoop},
+	"fd_filestat_get":         {[]ValueKind{I32, I32}, (*wasiSandboxState).fdFilestatGet},
+	"fd_filestat_set_size":    {[]ValueKind{I32, I64}, (*wasiSandboxState).fdFilestatSetSize},
+	"fd_filestat_set_times":   {[]ValueKind{I32, I64, I64, I32}, (*wasiSandboxState).fdNoop},
+	"fd_pread":                {[]ValueKind{I32, I32, I32, I64, I32}, (*wasiSandboxState).fdPread},
+	"fd_prestat_get":          {[]ValueKind{I32, I32}, (*wasiSandboxState).fdPrestatGet},
+	"fd_prestat_dir_name":     {[]ValueKind{I32, I32, I32}, (*wasiSandboxState).fdPrestatDirName},
+	"fd_pwrite":               {[]ValueKind{I32, I32, I32, I64, I32}, (*wasiSandboxState).fdPwrite},
+	"fd_read":                 {[]ValueKind{I32, I32, I32, I32}, (*wasiSandboxState).fdRead},
+	"fd_readdir":              {[]ValueKind{I32, I32, I32, I64, I32}, (*wasiSandboxState).fdReaddir},
+	"fd_renumber":             {[]ValueKind{I32, I32}, (*wasiSandboxState).fdRenumber},
+	"fd_seek":                 {[]ValueKind{I32, I64, I32, I32}, (*wasiSandboxState).fdSeek},
+	"fd_sync":                 {[]ValueKind{I32}, (*wasiSandboxState).fdNoop},
+	"fd_tell":                 {[]ValueKind{I32, I32}, (*wasiSandboxState).fdTell},
+	"fd_write":                {[]ValueKind{I32, I32, I32, I32}, (*wasiSandboxState).fdWrite},
+	"path_create_directory":   {[]ValueKind{I32, I32, I32}, (*wasiSandboxState).pathCreateDirectory},
+	"path_filestat_get":       {[]ValueKind{I32, I32, I32, I32, I32}, (*wasiSandboxState).pathFilestatGet},
+	"path_filestat_set_times": {[]ValueKind{I32, I32, I32, I32, I64, I64, I32}, (*wasiSandboxState).notSupported},
+	"path_link":               {[]ValueKind{I32, I32, I32, I32, I32, I32, I32}, (*wasiSandboxState).notSupported},
+	"path_open":               {[]ValueKind{I32, I32, I32, I32, I32, I64, I64, I32, I32}, (*wasiSandboxState).pathOpen},
+	"path_readlink":           {[]ValueKind{I32, I32, I32, I32, I32, I32}, (*wasiSandboxState).pathReadlink},
+	"path_remove_directory":   {[]ValueKind{I32, I32, I32}, (*wasiSandboxState).pathRemoveDirectory},
+	"path_rename":             {[]ValueKind{I32, I32, I32, I32, I32, I32}, (*wasiSandboxState).pathRename},
+	"path_symlink":            {[]ValueKind{I32, I32, I32, I32, I32}, (*wasiSandboxState).notSupported},
+	"path_unlink_file":        {[]ValueKind{I32, I32, I32}, (*wasiSandboxState).pathUnlinkFile},
+	"poll_oneoff":             {[]ValueKind{I32, I32, I32, I32}, (*wasiSandboxState).pollOneoff},
+	"random_get":              {[]ValueKind{I32, I32}, (*wasiSandboxState).randomGet},
+	"sock_accept":             {[]ValueKind{I32, I32, I32}, (*wasiSandboxState).notSupported},
+	"sock_recv":               {[]ValueKind{I32, I32, I32, I32, I32, I32}, (*wasiSandboxState).notSupported},
+	"sock_send":               {[]ValueKind{I32, I32, I32, I32, I32}, (*wasiSandboxState).notSupported},
+	"sock_shutdown":           {[]ValueKind{I32, I32}, (*wasiSandboxState).notSupported},
+}
+
+// pointer returns the nth argument, a pointer or a length.
+func pointer(args []Value, nth int) int64 {
+	return int64(uint32(args[nth].I32()))
+}
+
+func (self *wasiSandboxState) writeUint32(offset int64, value uint32) wasiErrno {
+	if err := self.memory.WriteUint32LE(offset, value); err != nil {
+		return wasiErrnoFault
+	}
+
+	return wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) writeUint64(offset int64, value uint64) wasiErrno {
+	var bytes [8]byte
+	binary.LittleEndian.PutUint64(bytes[:], value)
+
+	return self.write(bytes[:], offset)
+}
+
+func (self *wasiSandboxState) write(p []byte, offset int64) wasiErrno {
+	if _, err := self.memory.WriteAt(p, offset); err != nil {
+		return wasiErrnoFault
+	}
+
+	return wasiErrnoSuccess
+}
+
+// file returns the open file descriptor fd.
+func (self *wasiSandboxState) file(fd int32) (*wasiFile, wasiErrno) {
+	file, exists := self.files[uint32(fd)]
+
+	if !exists {
+		return nil, wasiErrnoBadf
+	}
+
+	return file, wasiErrnoSuccess
+}
+
+// regularFile returns the open file descriptor fd, if it is neither a
+// directory nor stdio.
+func (self *wasiSandboxState) regularFile(fd int32) (*wasiFile, wasiErrno) {
+	file, errno := self.file(fd)
+
+	switch {
+	case errno != wasiErrnoSuccess:
+		return nil, errno
+	case file.stdio():
+		return nil, wasiErrnoSpipe
+	case file.dir:
+		return nil, wasiErrnoIsdir
+	}
+
+	return file, wasiErrnoSuccess
+}
+
+// directory returns the open directory fd.
+func (self *wasiSandboxState) directory(fd int32) (*wasiFile, wasiErrno) {
+	file, errno := self.file(fd)
+
+	if errno != wasiErrnoSuccess {
+		return nil, errno
+	}
+
+	if !file.dir {
+		return nil, wasiErrnoNotdir
+	}
+
+	return file, wasiErrnoSuccess
+}
+
+// path reads the path at offset, relative to the directory, and
+// returns its name in the filesystem of the mount. A path outside of
+// the mount is refused.
+func (self *wasiSandboxState) path(directory *wasiFile, offset int64, length int64) (string, wasiErrno) {
+	if length > wasiMaxPathLength {
+		return "", wasiErrnoNametoolong
+	}
+
+	bytes, err := self.memory.ReadSlice(offset, length)
+
+	if err != nil {
+		return "", wasiErrnoFault
+	}
+
+	guestPath := string(bytes)
+
+	switch {
+	case guestPath == "":
+		return "", wasiErrnoNoent
+	case strings.IndexByte(guestPath, 0) >= 0:
+		return "", wasiErrnoInval
+	case path.IsAbs(guestPath):
+		return "", wasiErrnoNotcapable
+	}
+
+	name := path.Join(directory.name, guestPath)
+
+	if name == ".." || strings.HasPrefix(name, "../") {
+		return "", wasiErrnoNotcapable
+	}
+
+	return name, wasiErrnoSuccess
+}
+
+// writableDirectory returns the open directory fd, if its mount is
+// read-write.
+func (self *wasiSandboxState) writableDirectory(fd int32) (*wasiFile, wasiErrno) {
+	directory, errno := self.directory(fd)
+
+	if errno != wasiErrnoSuccess {
+		return nil, errno
+	}
+
+	if directory.mount.access != WASI_READ_WRITE {
+		return nil, wasiErrnoRofs
+	}
+
+	return directory, wasiErrnoSuccess
+}
+
+// iovecs reads count iovecs at offset, the buffer and length pairs of
+// fd_read and fd_write.
+func (self *wasiSandboxState) iovecs(offset int64, count int64) ([][2]int64, wasiErrno) {
+	bytes, err := self.memory.ReadSlice(offset, count*8)
+
+	if err != nil {
+		return nil, wasiErrnoFault
+	}
+
+	iovecs := make([][2]int64, count)
+
+	for nth := range iovecs {
+		buffer := int64(binary.LittleEndian.Uint32(bytes[nth*8:]))
+		length := int64(binary.LittleEndian.Uint32(bytes[nth*8+4:]))
+
+		if self.memory.checkBounds(buffer, length) != nil {
+			return nil, wasiErrnoFault
+		}
+
+		iovecs[nth] = [2]int64{buffer, length}
+	}
+
+	return iovecs, wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) notSupported([]Value) wasiErrno {
+	return wasiErrnoNotsup
+}
+
+// fdNoop accepts the call if fd is open; there is nothing to sync,
+// advise or change.
+func (self *wasiSandboxState) fdNoop(args []Value) wasiErrno {
+	_, errno := self.file(args[0].I32())
+
+	return errno
+}
+
+func (self *wasiSandboxState) clockResGet(args []Value) wasiErrno {
+	if id := args[0].I32(); id < 0 || id > 3 {
+		return wasiErrnoInval
+	}
+
+	resolution := self.sandbox.clockStep
+
+	if resolution <= 0 {
+		resolution = 1
+	}
+
+	return self.writeUint64(pointer(args, 1), uint64(resolution))
+}
+
+// now returns the time of the sandbox clock, without reading it.
+func (self *wasiSandboxState) now() time.Time {
+	return self.sandbox.clockStart.Add(time.Duration(self.ticks) * self.sandbox.clockStep)
+}
+
+func (self *wasiSandboxState) clockTimeGet(args []Value) wasiErrno {
+	if id := args[0].I32(); id < 0 || id > 3 {
+		return wasiErrnoInval
+	}
+
+	now := self.now()
+	self.ticks++
+
+	return self.writeUint64(pointer(args, 2), uint64(now.UnixNano()))
+}
+
+func (self *wasiSandboxState) randomGet(args []Value) wasiErrno {
+	offset, length := pointer(args, 0), pointer(args, 1)
+
+	if self.memory.checkBounds(offset, length) != nil {
+		return wasiErrnoFault
+	}
+
+	bytes := make([]byte, length)
+
+	if _, err := io.ReadFull(self.random, bytes); err != nil {
+		return wasiErrnoIo
+	}
+
+	return self.write(bytes, offset)
+}
+
+// pollOneoff never blocks: the file descriptors are always ready, and
+// when none is polled the clock moves to the earliest timeout instead
+// of sleeping.
+func (self *wasiSandboxState) pollOneoff(args []Value) wasiErrno {
+	count := pointer(args, 2)
+
+	if count == 0 {
+		return wasiErrnoInval
+	}
+
+	subscriptions, err := self.memory.ReadSlice(pointer(args, 0), count*wasiSubscriptionSize)
+
+	if err != nil {
+		return wasiErrnoFault
+	}
+
+	var events []byte
+	var clocks [][]byte
+	var timeouts []int64
+
+	for nth := int64(0); nth < count; nth++ {
+		subscription := subscriptions[nth*wasiSubscriptionSize : (nth+1)*wasiSubscriptionSize]
+		eventtype := subscription[8]
+
+		switch eventtype {
+		case wasiEventtypeClock:
+			if id := binary.LittleEndian.Uint32(subscription[16:]); id > 3 {
+				return wasiErrnoInval
+			}
+
+			timeout := int64(binary.LittleEndian.Uint64(subscription[24:]))
+
+			if binary.LittleEndian.Uint16(subscription[40:])&wasiSubclockflagsAbstime != 0 {
+				timeout -= self.now().UnixNano()
+			}
+
+			clocks = append(clocks, subscription)
+			timeouts = append(timeouts, timeout)
+		case wasiEventtypeFdRead, wasiEventtypeFdWrite:
+			errno, available := self.pollFile(int32(binary.LittleEndian.Uint32(subscription[16:])), eventtype)
+			events = appendWasiEvent(events, subscription, errno, available)
+		default:
+			return wasiErrnoInval
+		}
+	}
+
+	if len(events) == 0 {
+		earliest := timeouts[0]
+
+		for _, timeout := range timeouts {
+			if timeout < earliest {
+				earliest = timeout
+			}
+		}
+
+		if step := int64(self.sandbox.clockStep); earliest > 0 && step > 0 {
+			self.ticks += (earliest + step - 1) / step
+		}
+
+		for nth, subscription := range clocks {
+			if timeouts[nth] <= earliest {
+				events = appendWasiEvent(events, subscription, wasiErrnoSuccess, 0)
+			}
+		}
+	}
+
+	if errno := self.write(events, pointer(args, 1)); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	return self.writeUint32(pointer(args, 3), uint32(len(events)/wasiEventSize))
+}
+
+// pollFile returns whether fd can be read or written, and how many
+// bytes are left to read.
+func (self *wasiSandboxState) pollFile(fd int32, eventtype uint8) (wasiErrno, int64) {
+	file, errno := self.file(fd)
+
+	switch {
+	case errno != wasiErrnoSuccess:
+		return errno, 0
+	case file.dir:
+		return wasiErrnoBadf, 0
+	case eventtype == wasiEventtypeFdRead && file.writer != nil:
+		return wasiErrnoBadf, 0
+	case eventtype == wasiEventtypeFdWrite && file.reader != nil:
+		return wasiErrnoBadf, 0
+	case eventtype == wasiEventtypeFdWrite || file.stdio():
+		return wasiErrnoSuccess, 0
+	}
+
+	if available := file.size() - file.offset; available > 0 {
+		return wasiErrnoSuccess, available
+	}
+
+	return wasiErrnoSuccess, 0
+}
+
+// appendWasiEvent appends the event of the subscription to events.
+func appendWasiEvent(events []byte, subscription []byte, errno wasiErrno, available int64) []byte {
+	var event [wasiEventSize]byte
+	copy(event[0:8], subscription[0:8])
+	binary.LittleEndian.PutUint16(event[8:], uint16(errno))
+	event[10] = subscription[8]
+
+	if subscription[8] != wasiEventtypeClock {
+		binary.LittleEndian.PutUint64(event[16:], uint64(available))
+	}
+
+	return append(events, event[:]...)
+}
+
+func (self *wasiSandboxState) fdClose(args []Value) wasiErrno {
+	if _, errno := self.file(args[0].I32()); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	delete(self.files, uint32(args[0].I32()))
+
+	return wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) fdRenumber(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if _, errno := self.file(args[1].I32()); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	delete(self.files, uint32(args[0].I32()))
+	self.files[uint32(args[1].I32())] = file
+
+	return wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) fdFdstatGet(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	var fdstat [24]byte
+	fdstat[0] = wasiFiletypeRegularFile
+
+	switch {
+	case file.stdio():
+		fdstat[0] = wasiFiletypeCharacterDevice
+	case file.dir:
+		fdstat[0] = wasiFiletypeDirectory
+	}
+
+	if file.append {
+		binary.LittleEndian.PutUint16(fdstat[2:], wasiFdflagsAppend)
+	}
+
+	binary.LittleEndian.PutUint64(fdstat[8:], wasiRightsAll)
+	binary.LittleEndian.PutUint64(fdstat[16:], wasiRightsAll)
+
+	return self.write(fdstat[:], pointer(args, 1))
+}
+
+func (self *wasiSandboxState) fdFdstatSetFlags(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	flags := args[1].I32()
+
+	if flags&^wasiFdflagsAppend != 0 {
+		return wasiErrnoNotsup
+	}
+
+	file.append = flags&wasiFdflagsAppend != 0
+
+	return wasiErrnoSuccess
+}
+
+// filestat writes the filestat of a file of the given type and size at
+// offset. Every time is 0.
+func (self *wasiSandboxState) filestat(offset int64, filetype uint8, size int64) wasiErrno {
+	var filestat [64]byte
+	filestat[16] = filetype
+	binary.LittleEndian.PutUint64(filestat[24:], 1)
+	binary.LittleEndian.PutUint64(filestat[32:], uint64(size))
+
+	return self.write(filestat[:], offset)
+}
+
+func (self *wasiSandboxState) fdFilestatGet(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	switch {
+	case errno != wasiErrnoSuccess:
+		return errno
+	case file.stdio():
+		return self.filestat(pointer(args, 1), wasiFiletypeCharacterDevice, 0)
+	case file.dir:
+		return self.filestat(pointer(args, 1), wasiFiletypeDirectory, 0)
+	}
+
+	return self.filestat(pointer(args, 1), wasiFiletypeRegularFile, file.size())
+}
+
+func (self *wasiSandboxState) fdFilestatSetSize(args []Value) wasiErrno {
+	file, errno := self.regularFile(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if !file.writable {
+		return wasiErrnoBadf
+	}
+
+	return self.resize(file, args[1].I64())
+}
+
+func (self *wasiSandboxState) fdPrestatGet(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if !file.preopen {
+		return wasiErrnoBadf
+	}
+
+	var prestat [8]byte
+	binary.LittleEndian.PutUint32(prestat[4:], uint32(len(file.mount.guestPath)))
+
+	return self.write(prestat[:], pointer(args, 1))
+}
+
+func (self *wasiSandboxState) fdPrestatDirName(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if !file.preopen {
+		return wasiErrnoBadf
+	}
+
+	name := file.mount.guestPath
+
+	if int64(len(name)) > pointer(args, 2) {
+		return wasiErrnoNametoolong
+	}
+
+	return self.write([]byte(name), pointer(args, 1))
+}
+
+func (self *wasiSandboxState) fdRead(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if file.writer != nil {
+		return wasiErrnoBadf
+	}
+
+	if file.dir {
+		return wasiErrnoIsdir
+	}
+
+	iovecs, errno := self.iovecs(pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	var read int64
+
+	if file.reader != nil {
+		for _, iovec := range iovecs {
+			buffer := make([]byte, iovec[1])
+			n, err := io.ReadFull(file.reader, buffer)
+
+			if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+				return wasiErrnoIo
+			}
+
+			self.write(buffer[:n], iovec[0])
+			read += int64(n)
+
+			if n < len(buffer) {
+				break
+			}
+		}
+	} else {
+		read = self.readIovecs(file, iovecs, file.offset)
+		file.offset += read
+	}
+
+	return self.writeUint32(pointer(args, 3), uint32(read))
+}
+
+func (self *wasiSandboxState) fdPread(args []Value) wasiErrno {
+	file, errno := self.regularFile(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	iovecs, errno := self.iovecs(pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if args[3].I64() < 0 {
+		return wasiErrnoInval
+	}
+
+	return self.writeUint32(pointer(args, 4), uint32(self.readIovecs(file, iovecs, args[3].I64())))
+}
+
+// readIovecs copies the content of file at offset to the iovecs, and
+// returns the number of bytes copied.
+func (self *wasiSandboxState) readIovecs(file *wasiFile, iovecs [][2]int64, offset int64) int64 {
+	var read int64
+
+	for _, iovec := range iovecs {
+		buffer := make([]byte, iovec[1])
+		n := file.readAt(buffer, offset+read)
+
+		self.write(buffer[:n], iovec[0])
+		read += int64(n)
+
+		if n < len(buffer) {
+			break
+		}
+	}
+
+	return read
+}
+
+// gather returns the bytes of the iovecs of fd_write and fd_pwrite.
+func (self *wasiSandboxState) gather(offset int64, count int64) ([]byte, wasiErrno) {
+	iovecs, errno := self.iovecs(offset, count)
+
+	if errno != wasiErrnoSuccess {
+		return nil, errno
+	}
+
+	var data []byte
+
+	for _, iovec := range iovecs {
+		bytes, _ := self.memory.ReadSlice(iovec[0], iovec[1])
+		data = append(data, bytes...)
+	}
+
+	return data, wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) fdWrite(args []Value) wasiErrno {
+	file, errno := self.file(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if file.dir {
+		return wasiErrnoIsdir
+	}
+
+	if file.reader != nil || (file.writer == nil && !file.writable) {
+		return wasiErrnoBadf
+	}
+
+	data, errno := self.gather(pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if file.writer != nil {
+		file.writer.Write(data)
+	} else {
+		offset := file.offset
+
+		if file.append {
+			offset = file.size()
+		}
+
+		if errno := self.writeAt(file, data, offset); errno != wasiErrnoSuccess {
+			return errno
+		}
+
+		file.offset = offset + int64(len(data))
+	}
+
+	return self.writeUint32(pointer(args, 3), uint32(len(data)))
+}
+
+func (self *wasiSandboxState) fdPwrite(args []Value) wasiErrno {
+	file, errno := self.regularFile(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if !file.writable {
+		return wasiErrnoBadf
+	}
+
+	data, errno := self.gather(pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	offset := args[3].I64()
+
+	if offset < 0 {
+		return wasiErrnoInval
+	}
+
+	if errno := self.writeAt(file, data, offset); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	return self.writeUint32(pointer(args, 4), uint32(len(data)))
+}
+
+// writeAt copies data to the file at offset, within the quotas.
+func (self *wasiSandboxState) writeAt(file *wasiFile, data []byte, offset int64) wasiErrno {
+	memoryFS := file.mount.memoryFS
+
+	memoryFS.lock.Lock()
+	defer memoryFS.lock.Unlock()
+
+	if end := offset + int64(len(data)); end > int64(len(file.node.data)) {
+		if errno := self.grow(memoryFS, file.node, end); errno != wasiErrnoSuccess {
+			return errno
+		}
+	}
+
+	copy(file.node.data[offset:], data)
+
+	return wasiErrnoSuccess
+}
+
+// resize sets the size of the file, within the quotas.
+func (self *wasiSandboxState) resize(file *wasiFile, size int64) wasiErrno {
+	if size < 0 {
+		return wasiErrnoInval
+	}
+
+	memoryFS := file.mount.memoryFS
+
+	memoryFS.lock.Lock()
+	defer memoryFS.lock.Unlock()
+
+	if size <= int64(len(file.node.data)) {
+		memoryFS.resize(file.node, size)
+
+		return wasiErrnoSuccess
+	}
+
+	return self.grow(memoryFS, file.node, size)
+}
+
+// grow grows the file to size if the quotas allow it, the lock of
+// memoryFS must be held.
+func (self *wasiSandboxState) grow(memoryFS *MemoryFS, node *memoryNode, size int64) wasiErrno {
+	if limit := self.sandbox.maxFileSize; limit > 0 && size > limit {
+		return wasiErrnoFbig
+	}
+
+	if limit := self.sandbox.maxTotalSize; limit > 0 && !node.removed && memoryFS.size+size-int64(len(node.data)) > limit {
+		return wasiErrnoNospc
+	}
+
+	memoryFS.resize(node, size)
+
+	return wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) fdSeek(args []Value) wasiErrno {
+	file, errno := self.regularFile(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	offset := args[1].I64()
+
+	switch args[2].I32() {
+	case 0:
+	case 1:
+		offset += file.offset
+	case 2:
+		offset += file.size()
+	default:
+		return wasiErrnoInval
+	}
+
+	if offset < 0 {
+		return wasiErrnoInval
+	}
+
+	file.offset = offset
+
+	return self.writeUint64(pointer(args, 3), uint64(offset))
+}
+
+func (self *wasiSandboxState) fdTell(args []Value) wasiErrno {
+	file, errno := self.regularFile(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	return self.writeUint64(pointer(args, 1), uint64(file.offset))
+}
+
+func (self *wasiSandboxState) fdReaddir(args []Value) wasiErrno {
+	directory, errno := self.directory(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	entries, err := fs.ReadDir(directory.mount.fsys, directory.name)
+
+	if err != nil {
+		return wasiErrnoOf(err)
+	}
+
+	names := []string{".", ".."}
+	types := []uint8{wasiFiletypeDirectory, wasiFiletypeDirectory}
+
+	for _, entry := range entries {
+		filetype := wasiFiletypeRegularFile
+
+		switch {
+		case entry.IsDir():
+			filetype = wasiFiletypeDirectory
+		case entry.Type()&fs.ModeSymlink != 0:
+			filetype = wasiFiletypeSymbolicLink
+		}
+
+		names = append(names, entry.Name())
+		types = append(types, filetype)
+	}
+
+	length := pointer(args, 2)
+	cookie := args[3].I64()
+
+	if cookie < 0 {
+		return wasiErrnoInval
+	}
+
+	var buffer []byte
+
+	for nth := cookie; nth < int64(len(names)) && int64(len(buffer)) < length; nth++ {
+		var dirent [24]byte
+		binary.LittleEndian.PutUint64(dirent[0:], uint64(nth+1))
+		binary.LittleEndian.PutUint32(dirent[16:], uint32(len(names[nth])))
+		dirent[20] = types[nth]
+
+		buffer = append(buffer, dirent[:]...)
+		buffer = append(buffer, names[nth]...)
+	}
+
+	// A truncated last entry tells the module to read again with a
+	// bigger buffer.
+	if int64(len(buffer)) > length {
+		buffer = buffer[:length]
+	}
+
+	if errno := self.write(buffer, pointer(args, 1)); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	return self.writeUint32(pointer(args, 4), uint32(len(buffer)))
+}
+
+// openFiles returns the number of open files, besides stdio and the
+// mounts.
+func (self *wasiSandboxState) openFiles() int {
+	count := 0
+
+	for _, file := range self.files {
+		if !file.stdio() && !file.preopen {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (self *wasiSandboxState) pathOpen(args []Value) wasiErrno {
+	directory, errno := self.directory(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	name, errno := self.path(directory, pointer(args, 2), pointer(args, 3))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	oflags := args[4].I32()
+	fdflags := args[7].I32()
+	mount := directory.mount
+	file := &wasiFile{
+		mount:    mount,
+		name:     name,
+		append:   fdflags&wasiFdflagsAppend != 0,
+		writable: uint64(args[5].I64())&wasiRightsFdWrite != 0 || oflags&(wasiOflagsCreat|wasiOflagsTrunc) != 0 || fdflags&wasiFdflagsAppend != 0,
+	}
+
+	if file.writable && mount.access != WASI_READ_WRITE {
+		return wasiErrnoRofs
+	}
+
+	if limit := self.sandbox.maxOpenFiles; limit > 0 && self.openFiles() >= limit {
+		return wasiErrnoMfile
+	}
+
+	info, err := fs.Stat(mount.fsys, name)
+
+	switch {
+	case err == nil && oflags&wasiOflagsCreat != 0 && oflags&wasiOflagsExcl != 0:
+		return wasiErrnoExist
+	case errors.Is(err, fs.ErrNotExist) && oflags&wasiOflagsCreat != 0:
+		mount.memoryFS.lock.Lock()
+		file.node, err = mount.memoryFS.create(name, false)
+		mount.memoryFS.lock.Unlock()
+	case err != nil:
+	case info.IsDir():
+		if file.writable {
+			return wasiErrnoIsdir
+		}
+
+		file.dir = true
+	case oflags&wasiOflagsDirectory != 0:
+		return wasiErrnoNotdir
+	case mount.memoryFS != nil:
+		mount.memoryFS.lock.Lock()
+		file.node, err = mount.memoryFS.lookup(name)
+
+		if err == nil && oflags&wasiOflagsTrunc != 0 {
+			mount.memoryFS.resize(file.node, 0)
+		}
+
+		mount.memoryFS.lock.Unlock()
+	default:
+		file.data, err = fs.ReadFile(mount.fsys, name)
+	}
+
+	if err != nil {
+		return wasiErrnoOf(err)
+	}
+
+	fd := uint32(0)
+
+	for self.files[fd] != nil {
+		fd++
+	}
+
+	if errno := self.writeUint32(pointer(args, 8), fd); errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	self.files[fd] = file
+
+	return wasiErrnoSuccess
+}
+
+func (self *wasiSandboxState) pathFilestatGet(args []Value) wasiErrno {
+	directory, errno := self.directory(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	name, errno := self.path(directory, pointer(args, 2), pointer(args, 3))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	info, err := fs.Stat(directory.mount.fsys, name)
+
+	if err != nil {
+		return wasiErrnoOf(err)
+	}
+
+	if info.IsDir() {
+		return self.filestat(pointer(args, 4), wasiFiletypeDirectory, 0)
+	}
+
+	return self.filestat(pointer(args, 4), wasiFiletypeRegularFile, info.Size())
+}
+
+func (self *wasiSandboxState) pathReadlink(args []Value) wasiErrno {
+	directory, errno := self.directory(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	name, errno := self.path(directory, pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	if _, err := fs.Stat(directory.mount.fsys, name); err != nil {
+		return wasiErrnoOf(err)
+	}
+
+	// There are no symbolic links.
+	return wasiErrnoInval
+}
+
+// change runs change on the MemoryFS of a read-write mount, with the
+// name of the path at the offset and length of the arguments.
+func (self *wasiSandboxState) change(args []Value, change func(memoryFS *MemoryFS, name string) error) wasiErrno {
+	directory, errno := self.writableDirectory(args[0].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	name, errno := self.path(directory, pointer(args, 1), pointer(args, 2))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	memoryFS := directory.mount.memoryFS
+
+	memoryFS.lock.Lock()
+	defer memoryFS.lock.Unlock()
+
+	return wasiErrnoOf(change(memoryFS, name))
+}
+
+func (self *wasiSandboxState) pathCreateDirectory(args []Value) wasiErrno {
+	return self.change(args, func(memoryFS *MemoryFS, name string) error {
+		if _, err := memoryFS.lookup(name); err == nil {
+			return fs.ErrExist
+		}
+
+		_, err := memoryFS.create(name, true)
+
+		return err
+	})
+}
+
+func (self *wasiSandboxState) pathRemoveDirectory(args []Value) wasiErrno {
+	return self.change(args, func(memoryFS *MemoryFS, name string) error {
+		return memoryFS.remove(name, true)
+	})
+}
+
+func (self *wasiSandboxState) pathUnlinkFile(args []Value) wasiErrno {
+	return self.change(args, func(memoryFS *MemoryFS, name string) error {
+		return memoryFS.remove(name, false)
+	})
+}
+
+func (self *wasiSandboxState) pathRename(args []Value) wasiErrno {
+	newDirectory, errno := self.writableDirectory(args[3].I32())
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	newName, errno := self.path(newDirectory, pointer(args, 4), pointer(args, 5))
+
+	if errno != wasiErrnoSuccess {
+		return errno
+	}
+
+	return self.change(args, func(memoryFS *MemoryFS, name string) error {
+		if newDirectory.mount.memoryFS != memoryFS {
+			return errCrossMount
+		}
+
+		return memoryFS.rename(name, newName)
+	})
+}
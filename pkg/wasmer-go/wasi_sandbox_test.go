@@ -0,0 +1,231 @@
+package wasmer
+
+import (
+	"encoding/binary"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wasiSandboxWat opens the path at 0 (of length 16 at most, padded
+// with NULs by the tests) with the oflags and rights of its
+// parameters, and writes or reads the 5 bytes at 32. poll polls the
+// subscription at 128 into the event at 192.
+const wasiSandboxWat = `(module
+	(import "wasi_snapshot_preview1" "path_open" (func $path_open (param i32 i32 i32 i32 i32 i64 i64 i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "fd_write" (func $fd_write (param i32 i32 i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "fd_read" (func $fd_read (param i32 i32 i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "clock_time_get" (func $clock_time_get (param i32 i64 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "random_get" (func $random_get (param i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "poll_oneoff" (func $poll_oneoff (param i32 i32 i32 i32) (result i32)))
+	(import "wasi_snapshot_preview1" "sock_send" (func $sock_send (param i32 i32 i32 i32 i32) (result i32)))
+	(memory (export "memory") 1)
+	;; the iovec of the 5 bytes at 32
+	(data (i32.const 16) "\20\00\00\00\05\00\00\00")
+	(func $open (param $length i32) (param $oflags i32) (param $rights i64) (result i32)
+		(call $path_open (i32.const 3) (i32.const 0) (i32.const 0) (local.get $length) (local.get $oflags)
+			(local.get $rights) (i64.const 0) (i32.const 0) (i32.const 64)))
+	(func (export "write") (param $length i32) (result i32)
+		(local $errno i32)
+		(local.set $errno (call $open (local.get $length) (i32.const 9) (i64.const 64)))
+		(if (local.get $errno) (then (return (local.get $errno))))
+		(call $fd_write (i32.load (i32.const 64)) (i32.const 16) (i32.const 1) (i32.const 68)))
+	(func (export "read") (param $length i32) (result i32)
+		(local $errno i32)
+		(local.set $errno (call $open (local.get $length) (i32.const 0) (i64.const 2)))
+		(if (local.get $errno) (then (return (local.get $errno))))
+		(call $fd_read (i32.load (i32.const 64)) (i32.const 16) (i32.const 1) (i32.const 68)))
+	(func (export "print") (result i32)
+		(call $fd_write (i32.const 1) (i32.const 16) (i32.const 1) (i32.const 68)))
+	(func (export "now") (result i64)
+		(drop (call $clock_time_get (i32.const 0) (i64.const 1) (i32.const 72)))
+		(i64.load (i32.const 72)))
+	(func (export "random") (result i64)
+		(drop (call $random_get (i32.const 72) (i32.const 8)))
+		(i64.load (i32.const 72)))
+	(func (export "poll") (param $eventtype i32) (param $fd i32) (param $timeout i64) (result i32)
+		(i32.store8 (i32.const 136) (local.get $eventtype))
+		(i32.store (i32.const 144) (local.get $fd))
+		(i64.store (i32.const 152) (local.get $timeout))
+		(call $poll_oneoff (i32.const 128) (i32.const 192) (i32.const 1) (i32.const 224)))
+	(func (export "send") (result i32)
+		(call $sock_send (i32.const 1) (i32.const 16) (i32.const 1) (i32.const 0) (i32.const 68))))`
+
+func newWasiSandboxInstance(t *testing.T, sandbox *WasiSandbox) (*Instance, *WasiEnvironment) {
+	store := NewStore(NewEngine())
+	module, err := NewModuleFromWat(store, wasiSandboxWat, nil)
+	assert.NoError(t, err)
+
+	wasiEnv, err := NewWasiStateBuilder("test-program").
+		Sandbox(sandbox).
+		Finalize()
+	assert.NoError(t, err)
+
+	importObject, err := wasiEnv.GenerateImportObject(store, module)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, importObject)
+	assert.NoError(t, err)
+
+	return instance, wasiEnv
+}
+
+// callWasiSandbox writes path and the 5 bytes of data to the memory,
+// and calls the function.
+func callWasiSandbox(t *testing.T, instance *Instance, function string, path string, data string) interface{} {
+	memory, err := instance.Exports.GetMemory("memory")
+	assert.NoError(t, err)
+
+	_, err = memory.WriteAt(append([]byte(path), make([]byte, 16-len(path))...), 0)
+	assert.NoError(t, err)
+	_, err = memory.WriteAt([]byte(data), 32)
+	assert.NoError(t, err)
+
+	call, err := instance.Exports.GetFunction(function)
+	assert.NoError(t, err)
+
+	result, err := call(len(path))
+	assert.NoError(t, err)
+
+	return result
+}
+
+func TestWasiSandboxReadWrite(t *testing.T) {
+	memoryFS := NewMemoryFS()
+	assert.NoError(t, memoryFS.WriteFile("in.txt", []byte("world")))
+
+	instance, wasiEnv := newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE))
+
+	assert.Equal(t, int32(0), callWasiSandbox(t, instance, "write", "out.txt", "hello"))
+	data, err := memoryFS.ReadFile("out.txt")
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+
+	assert.Equal(t, int32(0), callWasiSandbox(t, instance, "read", "in.txt", "....."))
+	memory, _ := instance.Exports.GetMemory("memory")
+	data, _ = memory.ReadSlice(32, 5)
+	assert.Equal(t, "world", string(data))
+
+	// a missing file, and a path outside of the mount
+	assert.Equal(t, int32(44), callWasiSandbox(t, instance, "read", "missing.txt", ""))
+	assert.Equal(t, int32(76), callWasiSandbox(t, instance, "read", "../in.txt", ""))
+
+	printStdout, _ := instance.Exports.GetFunction("print")
+	_, err = printStdout()
+	assert.NoError(t, err)
+	assert.Equal(t, "world", string(wasiEnv.ReadStdout()))
+}
+
+func TestWasiSandboxReadOnly(t *testing.T) {
+	fsys := fstest.MapFS{"in.txt": {Data: []byte("hello")}}
+	instance, _ := newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", fsys, WASI_READ_ONLY))
+
+	assert.Equal(t, int32(0), callWasiSandbox(t, instance, "read", "in.txt", "....."))
+	assert.Equal(t, int32(69), callWasiSandbox(t, instance, "write", "in.txt", "world"))
+	assert.Equal(t, "hello", string(fsys["in.txt"].Data))
+
+	// only a MemoryFS can be mounted read-write
+	_, err := NewWasiStateBuilder("test-program").
+		Sandbox(NewWasiSandbox().Mount("/", fsys, WASI_READ_WRITE)).
+		Finalize()
+	assert.Error(t, err)
+}
+
+func TestWasiSandboxQuotas(t *testing.T) {
+	memoryFS := NewMemoryFS()
+	assert.NoError(t, memoryFS.WriteFile("in.txt", []byte("hello")))
+
+	instance, _ := newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE).MaxFileSize(4))
+	assert.Equal(t, int32(22), callWasiSandbox(t, instance, "write", "out.txt", "hello"))
+
+	instance, _ = newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE).MaxTotalSize(8))
+	assert.Equal(t, int32(51), callWasiSandbox(t, instance, "write", "out.txt", "hello"))
+	assert.Equal(t, int64(5), memoryFS.Size())
+
+	instance, _ = newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE).MaxOpenFiles(1))
+	assert.Equal(t, int32(0), callWasiSandbox(t, instance, "read", "in.txt", "....."))
+	assert.Equal(t, int32(33), callWasiSandbox(t, instance, "read", "in.txt", "....."))
+
+	// 0 means no limit
+	instance, _ = newWasiSandboxInstance(t, NewWasiSandbox().Mount("/", memoryFS, WASI_READ_WRITE).MaxOpenFiles(0))
+	assert.Equal(t, int32(0), callWasiSandbox(t, instance, "read", "in.txt", "....."))
+}
+
+func TestWasiSandboxClockAndRandom(t *testing.T) {
+	sandbox := NewWasiSandbox().Clock(time.Unix(100, 0), time.Second).RandomSeed(42)
+	instance, _ := newWasiSandboxInstance(t, sandbox)
+
+	now, _ := instance.Exports.GetFunction("now")
+	first, err := now()
+	assert.NoError(t, err)
+	second, err := now()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(100*time.Second), first)
+	assert.Equal(t, int64(101*time.Second), second)
+
+	// every instance of the sandbox reads the same bytes
+	var expected [8]byte
+	state, _ := sandbox.newState()
+	_, _ = state.random.Read(expected[:])
+
+	for nth := 0; nth < 2; nth++ {
+		instance, _ := newWasiSandboxInstance(t, sandbox)
+		random, _ := instance.Exports.GetFunction("random")
+		result, err := random()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(binary.LittleEndian.Uint64(expected[:])), result)
+	}
+}
+
+func TestWasiSandboxPoll(t *testing.T) {
+	instance, _ := newWasiSandboxInstance(t, NewWasiSandbox().Clock(time.Unix(100, 0), time.Second))
+	memory, _ := instance.Exports.GetMemory("memory")
+	poll, _ := instance.Exports.GetFunction("poll")
+	now, _ := instance.Exports.GetFunction("now")
+
+	// the clock moves to the timeout, rounded up to a step
+	result, err := poll(0, 0, int64(4500*time.Millisecond))
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), result)
+	result, _ = now()
+	assert.Equal(t, int64(105*time.Second), result)
+
+	events, _ := memory.ReadUint32LE(224)
+	assert.Equal(t, uint32(1), events)
+
+	// stdin is ready to read but not to write, fd 9 is not open
+	for _, test := range []struct {
+		eventtype int32
+		fd        int32
+		errno     uint32
+	}{{1, 0, 0}, {2, 0, 8}, {2, 1, 0}, {1, 9, 8}} {
+		result, err := poll(test.eventtype, test.fd, int64(0))
+		assert.NoError(t, err)
+		assert.Equal(t, int32(0), result)
+
+		event, _ := memory.ReadUint32LE(200)
+		assert.Equal(t, test.errno, event&0xffff)
+		assert.Equal(t, uint32(test.eventtype), event>>16&0xff)
+	}
+
+	send, _ := instance.Exports.GetFunction("send")
+	result, err = send()
+	assert.NoError(t, err)
+	assert.Equal(t, int32(58), result)
+}
+
+func TestWasiSandboxHostDirectories(t *testing.T) {
+	_, err := NewWasiStateBuilder("test-program").
+		PreopenDirectory(".").
+		Sandbox(NewWasiSandbox()).
+		Finalize()
+	assert.Error(t, err)
+
+	_, err = NewWasiStateBuilder("test-program").
+		MapDirectory("the_host_current_directory", ".").
+		Sandbox(NewWasiSandbox()).
+		Finalize()
+	assert.Error(t, err)
+}
@@ -1,7 +1,9 @@
 package wasmer
 
 import (
+	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
 	"testing"
 )
 
@@ -21,42 +23,90 @@ func TestWasiGetVersion(t *testing.T) {
 	assert.Equal(t, GetWasiVersion(module), WASI_VERSION_SNAPSHOT1)
 }
 
-//func TestWasiWithCapturedStdout(t *testing.T) {
-//	engine := NewEngine()
-//	store := NewStore(engine)
-//	module, err := NewModule(store, testGetBytes("wasi.wasm"))
-//	assert.NoError(t, err)
-//
-//	wasiEnv, err := NewWasiStateBuilder("test-program").
-//		Argument("--foo").
-//		Environment("ABC", "DEF").
-//		Environment("X", "ZY").
-//		MapDirectory("the_host_current_directory", ".").
-//		CaptureStdout().
-//		Finalize()
-//	assert.NoError(t, err)
-//
-//	importObject, err := wasiEnv.GenerateImportObject(store, module)
-//
-//	instance, err := NewInstance(module, importObject)
-//	assert.NoError(t, err)
-//
-//	start, err := instance.Exports.GetWasiStartFunction()
-//	assert.NoError(t, err)
-//
-//	_, err = start()
-//	if err != nil {
-//		t.Fatalf("execute error: %v", err)
-//	}
-//
-//	stdout := string(wasiEnv.ReadStdout())
-//
-//	assert.Equal(
-//		t,
-//		stdout,
-//		"Found program name: `test-program`\n"+
-//			"Found 1 arguments: --foo\n"+
-//			"Found 2 environment variables: ABC=DEF, X=ZY\n"+
-//			"Found 1 preopened directories: DirEntry(\"/the_host_current_directory\")\n",
-//	)
-//}
+func TestWasiWithCapturedStdout(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	module, err := NewModule(store, testGetBytes("wasi.wasm"), nil)
+	assert.NoError(t, err)
+
+	wasiEnv, err := NewWasiStateBuilder("test-program").
+		Argument("--foo").
+		Environment("ABC", "DEF").
+		Environment("X", "ZY").
+		MapDirectory("the_host_current_directory", ".").
+		CaptureStdout().
+		Finalize()
+	assert.NoError(t, err)
+
+	importObject, err := wasiEnv.GenerateImportObject(store, module)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, importObject)
+	assert.NoError(t, err)
+
+	start, err := instance.Exports.GetWasiStartFunction()
+	assert.NoError(t, err)
+
+	_, err = start()
+	if err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+
+	stdout := string(wasiEnv.ReadStdout())
+
+	assert.Equal(
+		t,
+		stdout,
+		"Found program name: `test-program`\n"+
+			"Found 1 arguments: --foo\n"+
+			"Found 2 environment variables: ABC=DEF, X=ZY\n"+
+			"Found 1 preopened directories: DirEntry(\"/the_host_current_directory\")\n",
+	)
+
+	// everything was read already
+	assert.Empty(t, wasiEnv.ReadStdout())
+}
+
+func TestWasiCaptureStdoutTo(t *testing.T) {
+	engine := NewEngine()
+	store := NewStore(engine)
+	module, err := NewModule(store, testGetBytes("wasi.wasm"), nil)
+	assert.NoError(t, err)
+
+	var lines []string
+	wasiEnv, err := NewWasiStateBuilder("test-program").
+		CaptureStdoutTo(NewLogWriter(func(template string, args ...interface{}) {
+			lines = append(lines, fmt.Sprintf(template, args...))
+		})).
+		CaptureStderr().
+		Finalize()
+	assert.NoError(t, err)
+
+	importObject, err := wasiEnv.GenerateImportObject(store, module)
+	assert.NoError(t, err)
+
+	instance, err := NewInstance(module, importObject)
+	assert.NoError(t, err)
+
+	start, err := instance.Exports.GetWasiStartFunction()
+	assert.NoError(t, err)
+
+	_, err = start()
+	assert.NoError(t, err)
+
+	assert.NoError(t, wasiEnv.CloseWriters())
+	assert.Equal(t, "Found program name: `test-program`", lines[0])
+
+	// closing prints a last line without a line ending
+	var last []string
+	writer := NewLogWriter(func(template string, args ...interface{}) {
+		last = append(last, fmt.Sprintf(template, args...))
+	})
+	_, _ = writer.Write([]byte("unterminated"))
+	assert.NoError(t, (&WasiEnvironment{stdoutWriter: writer}).closeWriters())
+	assert.Equal(t, []string{"unterminated"}, last)
+
+	stderr, err := io.ReadAll(wasiEnv.Stderr())
+	assert.NoError(t, err)
+	assert.Empty(t, stderr)
+}
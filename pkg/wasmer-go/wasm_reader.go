@@ -0,0 +1,210 @@
+package wasmer
+
+import (
+	"fmt"
+)
+
+// The size of the `\0asm` magic and of the version that start a
+// binary Wasm module.
+const wasmHeaderLength = 8
+
+// WasmReader reads the binary format of a module, see
+// https://webassembly.github.io/spec/core/binary/index.html. Every
+// read fails with an Error past the end of the bytes.
+//
+//   reader := NewWasmReader(section.Content)
+//   count, err := reader.U32()
+type WasmReader struct {
+	bytes  []byte
+	offset int
+}
+
+// NewWasmReader instantiates a new WasmReader reading bytes from the
+// start.
+func NewWasmReader(bytes []byte) *WasmReader {
+	return &WasmReader{bytes: bytes}
+}
+
+// Offset returns the offset of the next byte to read.
+func (self *WasmReader) Offset() int {
+	return self.offset
+}
+
+// EOF returns true once every byte is read.
+func (self *WasmReader) EOF() bool {
+	return self.offset >= len(self.bytes)
+}
+
+func (self *WasmReader) unexpectedEnd() error {
+	return newErrorWith(fmt.Sprintf("Unexpected end at offset %d", self.offset))
+}
+
+// Byte reads a byte.
+func (self *WasmReader) Byte() (byte, error) {
+	if self.EOF() {
+		return 0, self.unexpectedEnd()
+	}
+
+	value := self.bytes[self.offset]
+	self.offset++
+
+	return value, nil
+}
+
+// Peek returns the next byte without reading it.
+func (self *WasmReader) Peek() (byte, error) {
+	if self.EOF() {
+		return 0, self.unexpectedEnd()
+	}
+
+	return self.bytes[self.offset], nil
+}
+
+// Bytes reads the next length bytes.
+func (self *WasmReader) Bytes(length uint32) ([]byte, error) {
+	if uint64(length) > uint64(len(self.bytes)-self.offset) {
+		return nil, self.unexpectedEnd()
+	}
+
+	value := self.bytes[self.offset : self.offset+int(length)]
+	self.offset += int(length)
+
+	return value, nil
+}
+
+// U32 reads an unsigned LEB128 u32.
+func (self *WasmReader) U32() (uint32, error) {
+	var value uint32
+
+	for shift := uint(0); shift < 35; shift += 7 {
+		b, err := self.Byte()
+
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint32(b&0x7f) << shift
+
+		if b&0x80 == 0 {
+			return value, nil
+		}
+	}
+
+	return 0, newErrorWith(fmt.Sprintf("U32 too long at offset %d", self.offset))
+}
+
+// SkipSigned skips a signed LEB128 of maxBits bits at most, whose
+// value isn't needed.
+func (self *WasmReader) SkipSigned(maxBits uint) error {
+	for read := uint(0); read < maxBits+7; read += 7 {
+		b, err := self.Byte()
+
+		if err != nil {
+			return err
+		}
+
+		if b&0x80 == 0 {
+			return nil
+		}
+	}
+
+	return newErrorWith(fmt.Sprintf("Signed integer too long at offset %d", self.offset))
+}
+
+// Vector reads a length, then returns the bytes that follow.
+func (self *WasmReader) Vector() ([]byte, error) {
+	length, err := self.U32()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return self.Bytes(length)
+}
+
+// Name reads a UTF-8 name prefixed by its length.
+func (self *WasmReader) Name() (string, error) {
+	bytes, err := self.Vector()
+
+	if err != nil {
+		return "", err
+	}
+
+	return string(bytes), nil
+}
+
+// Limits reads the limits of a memory or of a table; the maximum is
+// nil when unbounded.
+func (self *WasmReader) Limits() (uint32, *uint32, error) {
+	flags, err := self.Byte()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	minimum, err := self.U32()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	// Bit 0 is set when there is a maximum, bit 1 when the memory is
+	// shared.
+	if flags&0x01 == 0 {
+		return minimum, nil, nil
+	}
+
+	maximum, err := self.U32()
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return minimum, &maximum, nil
+}
+
+// WasmSection is a section of a binary Wasm module.
+type WasmSection struct {
+	ID byte
+	// The offset of the content in the module.
+	Offset  int
+	Content []byte
+}
+
+// ReadWasmSections splits a binary Wasm module into its sections. If
+// a section is malformed, the sections before it are returned with
+// the Error.
+//
+//   sections, err := ReadWasmSections(wasmBytes)
+func ReadWasmSections(wasmBytes []byte) ([]*WasmSection, error) {
+	if len(wasmBytes) < wasmHeaderLength || !IsWasmBinary(wasmBytes) {
+		return nil, newErrorWith("Bytes are not a binary Wasm module")
+	}
+
+	reader := NewWasmReader(wasmBytes)
+	reader.offset = wasmHeaderLength
+
+	var sections []*WasmSection
+
+	for !reader.EOF() {
+		id, err := reader.Byte()
+
+		if err != nil {
+			return sections, err
+		}
+
+		content, err := reader.Vector()
+
+		if err != nil {
+			return sections, newErrorWith(fmt.Sprintf("Section %d: %s", id, err.Error()))
+		}
+
+		sections = append(sections, &WasmSection{
+			ID:      id,
+			Offset:  reader.offset - len(content),
+			Content: content,
+		})
+	}
+
+	return sections, nil
+}
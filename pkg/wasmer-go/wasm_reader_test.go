@@ -0,0 +1,54 @@
+package wasmer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWasmReader(t *testing.T) {
+	reader := NewWasmReader([]byte{0xe5, 0x8e, 0x26, 0x03, 'a', 'b', 'c', 0x01, 0x02, 0x03})
+
+	value, err := reader.U32()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(624485), value)
+
+	name, err := reader.Name()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", name)
+
+	minimum, maximum, err := reader.Limits()
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(2), minimum)
+	assert.Equal(t, uint32(3), *maximum)
+	assert.True(t, reader.EOF())
+
+	_, err = reader.Byte()
+	assert.Error(t, err)
+}
+
+func TestReadWasmSections(t *testing.T) {
+	wasmBytes, err := Wat2Wasm(`(module (memory 1) (func (export "f")))`)
+	assert.NoError(t, err)
+
+	sections, err := ReadWasmSections(wasmBytes)
+	assert.NoError(t, err)
+
+	var ids []byte
+
+	for _, section := range sections {
+		ids = append(ids, section.ID)
+		assert.Equal(t, wasmBytes[section.Offset:section.Offset+len(section.Content)], section.Content)
+	}
+
+	// type, function, memory, export and code
+	assert.Equal(t, []byte{1, 3, 5, 7, 10}, ids)
+
+	// the sections before a truncated one are returned
+	sections, err = ReadWasmSections(wasmBytes[:len(wasmBytes)-1])
+	assert.Error(t, err)
+	assert.Len(t, sections, 4)
+
+	_, err = ReadWasmSections([]byte("(module)"))
+	assert.Error(t, err)
+}
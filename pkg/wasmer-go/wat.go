@@ -3,10 +3,70 @@ package wasmer
 // #include <wasmer.h>
 import "C"
 import (
+	"bytes"
+	"regexp"
+	"strconv"
 	"unsafe"
 )
 
+// wasmMagic starts every binary Wasm module.
+var wasmMagic = []byte("\x00asm")
+
+// watPosition matches the position wat2wasm reports, e.g.
+// `--> <anon>:1:8`.
+var watPosition = regexp.MustCompile(`--> <anon>:(\d+):(\d+)`)
+
+// IsWasmBinary checks whether the bytes start with the `\0asm` magic
+// of a binary Wasm module, otherwise they are treated as WAT code.
+//
+//   IsWasmBinary([]byte("\x00asm\x01\x00\x00\x00")) // true
+//   IsWasmBinary([]byte("(module)")) // false
+func IsWasmBinary(wasm []byte) bool {
+	return bytes.HasPrefix(wasm, wasmMagic)
+}
+
+// WatError represents an error produced while parsing WAT code, with
+// the line and column it occurred at.
+type WatError struct {
+	message string
+	line    int
+	column  int
+}
+
+func newWatError(err *Error) error {
+	position := watPosition.FindStringSubmatch(err.message)
+	if position == nil {
+		return err
+	}
+
+	line, _ := strconv.Atoi(position[1])
+	column, _ := strconv.Atoi(position[2])
+
+	return &WatError{
+		message: err.message,
+		line:    line,
+		column:  column,
+	}
+}
+
+// Error returns the WatError's message.
+func (self *WatError) Error() string {
+	return self.message
+}
+
+// Line returns the line, starting at 1, the WatError occurred at.
+func (self *WatError) Line() int {
+	return self.line
+}
+
+// Column returns the column, starting at 1, the WatError occurred at.
+func (self *WatError) Column() int {
+	return self.column
+}
+
 // Wat2Wasm parses a string as either WAT code or a binary Wasm module.
+// A binary Wasm module is returned as is; a WAT parse error is
+// returned as a WatError when its position is known.
 //
 // See https://webassembly.github.io/spec/core/text/index.html.
 //
@@ -18,10 +78,24 @@ import (
 //   store := wasmer.NewStore(engine)
 //   module, _ := wasmer.NewModule(store, wasmBytes)
 func Wat2Wasm(wat string) ([]byte, error) {
+	if IsWasmBinary([]byte(wat)) {
+		return []byte(wat), nil
+	}
+
+	return wat2Wasm([]byte(wat))
+}
+
+func wat2Wasm(wat []byte) ([]byte, error) {
 	var watBytes C.wasm_byte_vec_t
-	var watLength = len(wat)
+	var watPointer *C.wasm_byte_t
+
+	// wasm_byte_vec_new copies the bytes, so there is nothing to free
+	// and NUL bytes are kept.
+	if len(wat) > 0 {
+		watPointer = (*C.wasm_byte_t)(unsafe.Pointer(&wat[0]))
+	}
 
-	C.wasm_byte_vec_new(&watBytes, C.size_t(watLength), C.CString(wat))
+	C.wasm_byte_vec_new(&watBytes, C.size_t(len(wat)), watPointer)
 	defer C.wasm_byte_vec_delete(&watBytes)
 
 	var wasm C.wasm_byte_vec_t
@@ -33,7 +107,7 @@ func Wat2Wasm(wat string) ([]byte, error) {
 	})
 
 	if err != nil {
-		return nil, err
+		return nil, newWatError(err)
 	}
 
 	defer C.wasm_byte_vec_delete(&wasm)
@@ -42,3 +116,12 @@ func Wat2Wasm(wat string) ([]byte, error) {
 
 	return wasmBytes, nil
 }
+
+// toWasm returns binary Wasm bytes as is and compiles WAT code.
+func toWasm(bytes []byte) ([]byte, error) {
+	if IsWasmBinary(bytes) {
+		return bytes, nil
+	}
+
+	return wat2Wasm(bytes)
+}
@@ -21,3 +21,17 @@ func TestBadWat2Wasm(t *testing.T) {
 	_, err := Wat2Wasm("(module")
 	assert.EqualError(t, err, "expected `)`\n     --> <anon>:1:8\n      |\n    1 | (module\n      |        ^")
 }
+
+func TestBadWat2WasmPosition(t *testing.T) {
+	_, err := Wat2Wasm("(module")
+	watErr, ok := err.(*WatError)
+	assert.True(t, ok)
+	assert.Equal(t, 1, watErr.Line())
+	assert.Equal(t, 8, watErr.Column())
+}
+
+func TestIsWasmBinary(t *testing.T) {
+	assert.True(t, IsWasmBinary([]byte("\x00asm\x01\x00\x00\x00")))
+	assert.False(t, IsWasmBinary([]byte("(module)")))
+	assert.False(t, IsWasmBinary(nil))
+}
@@ -0,0 +1,228 @@
+package wavm
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"chainmaker.org/chainmaker/protocol/v2"
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+)
+
+// LintRuleABI an export is missing or doesn't have the declared signature
+const LintRuleABI = "abi"
+
+// FunctionSignature the parameter and result types of a function
+type FunctionSignature struct {
+	Params  []wasmergo.ValueKind
+	Results []wasmergo.ValueKind
+}
+
+// String return the signature as (i32) -> (i32)
+func (s *FunctionSignature) String() string {
+	return fmt.Sprintf("(%s) -> (%s)", joinKinds(s.Params), joinKinds(s.Results))
+}
+
+// matches return true if ty has exactly the types of the signature
+func (s *FunctionSignature) matches(ty *wasmergo.FunctionType) bool {
+	return sameKinds(s.Params, ty.Params()) && sameKinds(s.Results, ty.Results())
+}
+
+// anyMatches return true if one of signatures matches ty
+func anyMatches(signatures []*FunctionSignature, ty *wasmergo.FunctionType) bool {
+	for _, signature := range signatures {
+		if signature.matches(ty) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinSignatures return the signatures as () -> () or () -> (i32)
+func joinSignatures(signatures []*FunctionSignature) string {
+	names := make([]string, 0, len(signatures))
+	for _, signature := range signatures {
+		names = append(names, signature.String())
+	}
+	return strings.Join(names, " or ")
+}
+
+func signatureOf(ty *wasmergo.FunctionType) *FunctionSignature {
+	signature := &FunctionSignature{}
+	for _, param := range ty.Params() {
+		signature.Params = append(signature.Params, param.Kind())
+	}
+	for _, result := range ty.Results() {
+		signature.Results = append(signature.Results, result.Kind())
+	}
+	return signature
+}
+
+func sameKinds(kinds []wasmergo.ValueKind, types []*wasmergo.ValueType) bool {
+	if len(kinds) != len(types) {
+		return false
+	}
+	for i, kind := range kinds {
+		if types[i].Kind() != kind {
+			return false
+		}
+	}
+	return true
+}
+
+func joinKinds(kinds []wasmergo.ValueKind) string {
+	names := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		names = append(names, kind.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// ContractABI the exports the host relies on when it calls a contract
+type ContractABI struct {
+	// name of the exported memory the host reads and writes
+	Memory string
+	// the functions the host calls itself, by name, all must be exported
+	Functions map[string]*FunctionSignature
+	// the signatures every other exported function, a contract method, may have, empty allows any
+	Methods []*FunctionSignature
+}
+
+// DefaultContractABI return the ABI of ChainMaker contracts, used when PoolOptions.ABI is nil
+func DefaultContractABI() *ContractABI {
+	return &ContractABI{
+		Memory: "memory",
+		Functions: map[string]*FunctionSignature{
+			protocol.ContractRuntimeTypeMethod: {Results: []wasmergo.ValueKind{wasmergo.I32}},
+			protocol.ContractAllocateMethod: {
+				Params:  []wasmergo.ValueKind{wasmergo.I32},
+				Results: []wasmergo.ValueKind{wasmergo.I32},
+			},
+			protocol.ContractDeallocateMethod: {Params: []wasmergo.ValueKind{wasmergo.I32}},
+		},
+		// a method returns nothing, or an i32 status
+		Methods: []*FunctionSignature{{}, {Results: []wasmergo.ValueKind{wasmergo.I32}}},
+	}
+}
+
+// CheckContractABI check the exports of module against abi, every missing
+// export and every signature mismatch is in the report
+func CheckContractABI(module *wasmergo.Module, abi *ContractABI) *LintReport {
+	report := &LintReport{}
+
+	exports := make(map[string]*wasmergo.ExternType)
+	for _, exportType := range module.Exports() {
+		exports[exportType.Name()] = exportType.Type()
+	}
+
+	if externType, ok := exports[abi.Memory]; !ok {
+		report.add(LintRuleABI, -1, -1, "memory %s is not exported", abi.Memory)
+	} else if externType.Kind() != wasmergo.MEMORY {
+		report.add(LintRuleABI, -1, -1, "%s is exported as %s, not a memory", abi.Memory, externType.Kind())
+	}
+
+	for _, exportType := range module.Exports() {
+		name := exportType.Name()
+		if _, ok := abi.Functions[name]; ok || exportType.Type().Kind() != wasmergo.FUNCTION || len(abi.Methods) == 0 {
+			continue
+		}
+		ty := exportType.Type().IntoFunctionType()
+		if !anyMatches(abi.Methods, ty) {
+			report.add(LintRuleABI, -1, -1, "method %s has signature %s, want %s", name, signatureOf(ty),
+				joinSignatures(abi.Methods))
+		}
+	}
+
+	for _, name := range sortedNames(abi.Functions) {
+		signature := abi.Functions[name]
+		externType, ok := exports[name]
+		if !ok {
+			report.add(LintRuleABI, -1, -1, "function %s is not exported", name)
+			continue
+		}
+		if externType.Kind() != wasmergo.FUNCTION {
+			report.add(LintRuleABI, -1, -1, "%s is exported as %s, not a function", name, externType.Kind())
+			continue
+		}
+		ty := externType.IntoFunctionType()
+		if !signature.matches(ty) {
+			report.add(LintRuleABI, -1, -1, "function %s has signature %s, want %s", name, signatureOf(ty),
+				signature)
+		}
+	}
+	return report
+}
+
+// contractExports the exports of one instance the host uses, resolved once
+// when the instance is created so an invoke doesn't look them up by name
+type contractExports struct {
+	memory     *wasmergo.Memory
+	allocate   *wasmergo.Function
+	deallocate *wasmergo.Function
+	// every other exported function, by name
+	methods map[string]*wasmergo.Function
+}
+
+// resolveExports look up the exports of instance, module passed CheckContractABI
+// against abi, so the host functions are there and have the right type
+func resolveExports(module *wasmergo.Module, instance *wasmergo.Instance, abi *ContractABI) (*contractExports, error) {
+	memory, err := instance.Exports.GetMemory(abi.Memory)
+	if err != nil || memory == nil {
+		return nil, fmt.Errorf("can't get exported memory %s, err = %v", abi.Memory, err)
+	}
+	exports := &contractExports{
+		memory:  memory,
+		methods: make(map[string]*wasmergo.Function),
+	}
+
+	for _, exportType := range module.Exports() {
+		if exportType.Type().Kind() != wasmergo.FUNCTION {
+			continue
+		}
+		name := exportType.Name()
+		function, err := instance.Exports.GetRawFunction(name)
+		if err != nil {
+			exports.close()
+			return nil, fmt.Errorf("can't get exported function %s, err = %v", name, err)
+		}
+		// build the native function now, an invoke only calls it
+		function.Native()
+
+		switch name {
+		case protocol.ContractAllocateMethod:
+			exports.allocate = function
+		case protocol.ContractDeallocateMethod:
+			exports.deallocate = function
+		default:
+			exports.methods[name] = function
+		}
+	}
+	return exports, nil
+}
+
+// method return the exported contract method, nil if there is none
+func (e *contractExports) method(name string) *wasmergo.Function {
+	return e.methods[name]
+}
+
+// close release the function types, before the instance is closed
+func (e *contractExports) close() {
+	for _, function := range []*wasmergo.Function{e.allocate, e.deallocate} {
+		if function != nil {
+			function.Close()
+		}
+	}
+	for name, function := range e.methods {
+		function.Close()
+		delete(e.methods, name)
+	}
+}
+
+func sortedNames(functions map[string]*FunctionSignature) []string {
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,78 @@
+package wavm
+
+import (
+	"testing"
+
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func checkABIWat(t *testing.T, wat string, abi *ContractABI) *LintReport {
+	byteCode, err := wasmergo.Wat2Wasm(wat)
+	assert.NoError(t, err)
+	store := wasmergo.NewStore(wasmergo.NewDeterministicEngine())
+	module, err := wasmergo.NewModule(store, byteCode, nil)
+	assert.NoError(t, err)
+	return CheckContractABI(module, abi)
+}
+
+func TestCheckContractABIRustCounter(t *testing.T) {
+	wasmBytes, _, _ := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+	store := wasmergo.NewStore(wasmergo.NewDeterministicEngine())
+	module, err := wasmergo.NewModule(store, wasmBytes, nil)
+	assert.NoError(t, err)
+	report := CheckContractABI(module, DefaultContractABI())
+	assert.True(t, report.OK(), report.Err())
+}
+
+func TestCheckContractABIMismatch(t *testing.T) {
+	wat := `(module
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "allocate") (param i64) (result i32) i32.const 0)
+	(func (export "add") (param i32 i32) (result i32) i32.const 0)
+	(func (export "status") (result i32) i32.const 0))`
+
+	report := checkABIWat(t, wat, DefaultContractABI())
+	assert.Equal(t, []string{LintRuleABI, LintRuleABI, LintRuleABI, LintRuleABI}, rules(report))
+	assert.Equal(t, "memory memory is not exported", report.Issues[0].Message)
+	assert.Equal(t, "method add has signature (i32, i32) -> (i32), want () -> () or () -> (i32)", report.Issues[1].Message)
+	assert.Equal(t, "function allocate has signature (i64) -> (i32), want (i32) -> (i32)", report.Issues[2].Message)
+	assert.Equal(t, "function deallocate is not exported", report.Issues[3].Message)
+
+	// any method signature is allowed without Methods
+	abi := DefaultContractABI()
+	abi.Methods = nil
+	report = checkABIWat(t, wat, abi)
+	assert.Len(t, report.Issues, 3)
+}
+
+func TestNewVmPoolChecksABI(t *testing.T) {
+	// a contract without allocate and deallocate
+	wasmBytes, err := wasmergo.Wat2Wasm(`(module
+	(memory (export "memory") 1)
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "increase")))`)
+	assert.NoError(t, err)
+	_, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	_, err = newVmPool(&contractId, wasmBytes, nil, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "function allocate is not exported")
+	assert.Contains(t, err.Error(), "function deallocate is not exported")
+}
+
+func TestNewVmPoolResolvesExports(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+	pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1}, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+
+	instance, err := pool.NewInstance()
+	assert.NoError(t, err)
+	defer pool.CloseInstance(instance)
+	assert.NotNil(t, instance.exports.memory)
+	assert.NotNil(t, instance.exports.allocate)
+	assert.NotNil(t, instance.exports.deallocate)
+	assert.NotNil(t, instance.exports.method("increase"))
+	assert.Nil(t, instance.exports.method("decrease"))
+}
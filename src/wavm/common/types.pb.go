@@ -20,6 +20,79 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ContractResultCode classifies the result of an invoke, the code of a ContractResult
+type ContractResultCode int32
+
+const (
+	// the contract returned successfully
+	ContractResultCode_OK ContractResultCode = 0
+	// the vm failed for another reason, every failure had this code before the codes were classified
+	ContractResultCode_INTERNAL ContractResultCode = 1
+	// the contract ran to the end but reported a business error
+	ContractResultCode_CONTRACT_ERROR ContractResultCode = 2
+	// the contract was interrupted because the invoke deadline expired
+	ContractResultCode_TIMEOUT ContractResultCode = 3
+	// the contract ran out of gas
+	ContractResultCode_OUT_OF_GAS ContractResultCode = 4
+	// the contract trapped, e.g. unreachable, out of bounds memory access, division by zero
+	ContractResultCode_TRAP ContractResultCode = 5
+	// the contract doesn't export the method
+	ContractResultCode_MISSING_METHOD ContractResultCode = 6
+	// no instance of the contract was available, the pool is closed or busy until the deadline
+	ContractResultCode_POOL_UNAVAILABLE ContractResultCode = 7
+)
+
+// Enum value maps for ContractResultCode.
+var (
+	ContractResultCode_name = map[int32]string{
+		0: "OK",
+		1: "INTERNAL",
+		2: "CONTRACT_ERROR",
+		3: "TIMEOUT",
+		4: "OUT_OF_GAS",
+		5: "TRAP",
+		6: "MISSING_METHOD",
+		7: "POOL_UNAVAILABLE",
+	}
+	ContractResultCode_value = map[string]int32{
+		"OK":               0,
+		"INTERNAL":         1,
+		"CONTRACT_ERROR":   2,
+		"TIMEOUT":          3,
+		"OUT_OF_GAS":       4,
+		"TRAP":             5,
+		"MISSING_METHOD":   6,
+		"POOL_UNAVAILABLE": 7,
+	}
+)
+
+func (x ContractResultCode) Enum() *ContractResultCode {
+	p := new(ContractResultCode)
+	*p = x
+	return p
+}
+
+func (x ContractResultCode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ContractResultCode) Descriptor() protoreflect.EnumDescriptor {
+	return file_types_proto_enumTypes[0].Descriptor()
+}
+
+func (ContractResultCode) Type() protoreflect.EnumType {
+	return &file_types_proto_enumTypes[0]
+}
+
+func (x ContractResultCode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ContractResultCode.Descriptor instead.
+func (ContractResultCode) EnumDescriptor() ([]byte, []int) {
+	return file_types_proto_rawDescGZIP(), []int{0}
+}
+
 type Contract struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -80,6 +153,7 @@ type ContractResult struct {
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
+	// a ContractResultCode
 	Code    uint32 `protobuf:"fixed32,1,opt,name=code,proto3" json:"code,omitempty"`
 	Result  []byte `protobuf:"bytes,2,opt,name=result,proto3" json:"result,omitempty"`
 	Message string `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
@@ -161,8 +235,17 @@ var file_types_proto_rawDesc = []byte{
 	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
 	0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x67, 0x61, 0x73, 0x5f, 0x75,
 	0x73, 0x65, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x06, 0x52, 0x07, 0x67, 0x61, 0x73, 0x55, 0x73,
-	0x65, 0x64, 0x42, 0x09, 0x5a, 0x07, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62, 0x06, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x65, 0x64, 0x2a, 0x8f, 0x01, 0x0a, 0x12, 0x43, 0x6f, 0x6e, 0x74, 0x72, 0x61, 0x63, 0x74, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x06, 0x0a, 0x02, 0x4f, 0x4b, 0x10,
+	0x00, 0x12, 0x0c, 0x0a, 0x08, 0x49, 0x4e, 0x54, 0x45, 0x52, 0x4e, 0x41, 0x4c, 0x10, 0x01, 0x12,
+	0x12, 0x0a, 0x0e, 0x43, 0x4f, 0x4e, 0x54, 0x52, 0x41, 0x43, 0x54, 0x5f, 0x45, 0x52, 0x52, 0x4f,
+	0x52, 0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55, 0x54, 0x10, 0x03,
+	0x12, 0x0e, 0x0a, 0x0a, 0x4f, 0x55, 0x54, 0x5f, 0x4f, 0x46, 0x5f, 0x47, 0x41, 0x53, 0x10, 0x04,
+	0x12, 0x08, 0x0a, 0x04, 0x54, 0x52, 0x41, 0x50, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x4d, 0x49,
+	0x53, 0x53, 0x49, 0x4e, 0x47, 0x5f, 0x4d, 0x45, 0x54, 0x48, 0x4f, 0x44, 0x10, 0x06, 0x12, 0x14,
+	0x0a, 0x10, 0x50, 0x4f, 0x4f, 0x4c, 0x5f, 0x55, 0x4e, 0x41, 0x56, 0x41, 0x49, 0x4c, 0x41, 0x42,
+	0x4c, 0x45, 0x10, 0x07, 0x42, 0x09, 0x5a, 0x07, 0x2f, 0x63, 0x6f, 0x6d, 0x6d, 0x6f, 0x6e, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -177,10 +260,12 @@ func file_types_proto_rawDescGZIP() []byte {
 	return file_types_proto_rawDescData
 }
 
+var file_types_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
 var file_types_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_types_proto_goTypes = []interface{}{
-	(*Contract)(nil),       // 0: common.Contract
-	(*ContractResult)(nil), // 1: common.ContractResult
+	(ContractResultCode)(0), // 0: common.ContractResultCode
+	(*Contract)(nil),        // 1: common.Contract
+	(*ContractResult)(nil),  // 2: common.ContractResult
 }
 var file_types_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -226,13 +311,14 @@ func file_types_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_types_proto_rawDesc,
-			NumEnums:      0,
+			NumEnums:      1,
 			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
 		GoTypes:           file_types_proto_goTypes,
 		DependencyIndexes: file_types_proto_depIdxs,
+		EnumInfos:         file_types_proto_enumTypes,
 		MessageInfos:      file_types_proto_msgTypes,
 	}.Build()
 	File_types_proto = out.File
@@ -10,6 +10,7 @@ package wavm
 import (
 	"fmt"
 	"github.com/jhyehuang/wasm-example/src/wavm/common"
+	"github.com/jhyehuang/wasm-example/src/wavm/txsim"
 	"io/ioutil"
 	"testing"
 
@@ -59,6 +60,17 @@ func fillingBaseParams(parameters map[string][]byte) {
 	parameters[protocol.ContractBlockHeightParam] = []byte("111")
 }
 
+func newTxSimContext(store *txsim.Store) *txsim.TxSimContext {
+	tx := &commonPb.Transaction{
+		Payload: &commonPb.Payload{
+			ChainId:      ChainId,
+			TxId:         "TX_ID",
+			ContractName: ContractName,
+		},
+	}
+	return txsim.NewTxSimContext(store, tx, 111, BlockVersion)
+}
+
 type SnapshotMock struct {
 	cache map[string][]byte
 }
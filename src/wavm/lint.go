@@ -0,0 +1,449 @@
+package wavm
+
+import (
+	"fmt"
+	"strings"
+
+	"chainmaker.org/chainmaker/protocol/v2"
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+)
+
+const (
+	// 64 MiB of linear memory
+	defaultLintMaxMemoryPages = 1024
+	defaultLintMaxTableSize   = 10000
+)
+
+// ids of the sections the linter reads
+const (
+	sectionTable  = 4
+	sectionMemory = 5
+	sectionStart  = 8
+	sectionCode   = 10
+)
+
+// lint rules, the Rule of a LintIssue
+const (
+	LintRuleFloat       = "float"
+	LintRuleInstruction = "instruction"
+	LintRuleImport      = "import"
+	LintRuleExport      = "export"
+	LintRuleMemoryLimit = "memory_limit"
+	LintRuleTableLimit  = "table_limit"
+	LintRuleStart       = "start"
+)
+
+// LintOptions what the linter rejects, zero fields take the default value
+type LintOptions struct {
+	// reject float instructions, they are not deterministic across hosts
+	ForbidFloat bool
+	// the imports a contract may use, as module.name, nil allows the host functions of wavm
+	AllowedImports []string
+	// the exports a contract must have, nil requires allocate, deallocate and the runtime-type method
+	RequiredExports []string
+	// the max initial and max size of a memory, in 64 KiB pages
+	MaxMemoryPages uint32
+	// the max initial and max size of a table, in elements
+	MaxTableSize uint32
+	// allow a start function, it would run outside of any transaction
+	AllowStart bool
+}
+
+// DefaultLintOptions return the options used when LintContract is given nil
+func DefaultLintOptions() *LintOptions {
+	return &LintOptions{
+		AllowedImports: []string{
+			importNamespace + "." + sysCallImport,
+			importNamespace + "." + logMessageImport,
+		},
+		RequiredExports: []string{
+			protocol.ContractAllocateMethod,
+			protocol.ContractDeallocateMethod,
+			protocol.ContractRuntimeTypeMethod,
+		},
+		MaxMemoryPages: defaultLintMaxMemoryPages,
+		MaxTableSize:   defaultLintMaxTableSize,
+	}
+}
+
+// withDefaults return a copy of the options with zero fields set to default
+func (o *LintOptions) withDefaults() *LintOptions {
+	options := DefaultLintOptions()
+	if o == nil {
+		return options
+	}
+	options.ForbidFloat = o.ForbidFloat
+	options.AllowStart = o.AllowStart
+	if o.AllowedImports != nil {
+		options.AllowedImports = o.AllowedImports
+	}
+	if o.RequiredExports != nil {
+		options.RequiredExports = o.RequiredExports
+	}
+	if o.MaxMemoryPages != 0 {
+		options.MaxMemoryPages = o.MaxMemoryPages
+	}
+	if o.MaxTableSize != 0 {
+		options.MaxTableSize = o.MaxTableSize
+	}
+	return options
+}
+
+// LintIssue one construct the contract is rejected for
+type LintIssue struct {
+	Rule    string
+	Message string
+	// index of the function the issue is in, -1 if not in code
+	Function int
+	// offset in the byte code, -1 if unknown
+	Offset int
+}
+
+func (i *LintIssue) String() string {
+	if i.Function < 0 {
+		return fmt.Sprintf("[%s] %s", i.Rule, i.Message)
+	}
+	return fmt.Sprintf("[%s] function %d at offset %d: %s", i.Rule, i.Function, i.Offset, i.Message)
+}
+
+// LintReport every issue found in a contract
+type LintReport struct {
+	Issues []*LintIssue
+}
+
+// OK return true if the contract has no issue
+func (r *LintReport) OK() bool {
+	return len(r.Issues) == 0
+}
+
+// Err return nil if the contract has no issue, otherwise an error listing them
+func (r *LintReport) Err() error {
+	if r.OK() {
+		return nil
+	}
+	issues := make([]string, 0, len(r.Issues))
+	for _, issue := range r.Issues {
+		issues = append(issues, issue.String())
+	}
+	return fmt.Errorf("contract rejected by linter, %d issue(s): %s", len(r.Issues), strings.Join(issues, "; "))
+}
+
+func (r *LintReport) add(rule string, function int, offset int, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, &LintIssue{
+		Rule:     rule,
+		Message:  fmt.Sprintf(format, args...),
+		Function: function,
+		Offset:   offset,
+	})
+}
+
+// LintContract check the contract against options at deployment time, module is
+// compiled from byteCode, binary or WAT, the imports and exports are read from module and the
+// memories, tables, start function and code from byteCode; the error is
+// for byte code the linter can't read, the issues are in the report
+func LintContract(module *wasmergo.Module, byteCode []byte, options *LintOptions) (*LintReport, error) {
+	options = options.withDefaults()
+	report := &LintReport{}
+
+	importedFuncs := lintImports(module, options, report)
+	lintExports(module, options, report)
+
+	// byteCode may be WAT, like the byte code NewModule compiles
+	wasmBytes, err := wasmergo.Wat2Wasm(string(byteCode))
+	if err != nil {
+		return nil, err
+	}
+	sections, err := wasmergo.ReadWasmSections(wasmBytes)
+	if err != nil {
+		return nil, err
+	}
+	for _, section := range sections {
+		switch section.ID {
+		case sectionMemory:
+			err = lintLimits(section, LintRuleMemoryLimit, "memory", options.MaxMemoryPages, report)
+		case sectionTable:
+			err = lintLimits(section, LintRuleTableLimit, "table", options.MaxTableSize, report)
+		case sectionStart:
+			if !options.AllowStart {
+				report.add(LintRuleStart, -1, section.Offset, "start function is not allowed")
+			}
+		case sectionCode:
+			err = lintCode(section, importedFuncs, options, report)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read section %d failed, %s", section.ID, err.Error())
+		}
+	}
+	return report, nil
+}
+
+// lintImports check the imports are allowed and the imported memories and tables
+// are small enough, return how many functions are imported
+func lintImports(module *wasmergo.Module, options *LintOptions, report *LintReport) int {
+	allowed := make(map[string]bool, len(options.AllowedImports))
+	for _, name := range options.AllowedImports {
+		allowed[name] = true
+	}
+
+	importedFuncs := 0
+	for _, importType := range module.Imports() {
+		name := importType.Module() + "." + importType.Name()
+		externType := importType.Type()
+		switch externType.Kind() {
+		case wasmergo.FUNCTION:
+			importedFuncs++
+		case wasmergo.MEMORY:
+			checkLimits(externType.IntoMemoryType().Limits(), LintRuleMemoryLimit, "imported memory "+name,
+				options.MaxMemoryPages, report)
+		case wasmergo.TABLE:
+			checkLimits(externType.IntoTableType().Limits(), LintRuleTableLimit, "imported table "+name,
+				options.MaxTableSize, report)
+		}
+		if !allowed[name] {
+			report.add(LintRuleImport, -1, -1, "import %s (%s) is not allowed", name, externType.Kind())
+		}
+	}
+	return importedFuncs
+}
+
+// lintExports check the required functions are exported
+func lintExports(module *wasmergo.Module, options *LintOptions, report *LintReport) {
+	exported := make(map[string]bool)
+	for _, exportType := range module.Exports() {
+		if exportType.Type().Kind() == wasmergo.FUNCTION {
+			exported[exportType.Name()] = true
+		}
+	}
+	for _, name := range options.RequiredExports {
+		if !exported[name] {
+			report.add(LintRuleExport, -1, -1, "required function %s is not exported", name)
+		}
+	}
+}
+
+func checkLimits(limits *wasmergo.Limits, rule string, what string, max uint32, report *LintReport) {
+	if limits.Minimum() > max {
+		report.add(rule, -1, -1, "%s initial size %d exceeds %d", what, limits.Minimum(), max)
+	}
+	if limits.Maximum() != wasmergo.LimitMaxUnbound() && limits.Maximum() > max {
+		report.add(rule, -1, -1, "%s max size %d exceeds %d", what, limits.Maximum(), max)
+	}
+}
+
+// lintLimits check the memories or tables defined by the module
+func lintLimits(section *wasmergo.WasmSection, rule string, what string, max uint32, report *LintReport) error {
+	r := wasmergo.NewWasmReader(section.Content)
+	count, err := r.U32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		if what == "table" {
+			// the element type
+			if _, err = r.Byte(); err != nil {
+				return err
+			}
+		}
+		offset := section.Offset + r.Offset()
+		min, limitMax, err := r.Limits()
+		if err != nil {
+			return err
+		}
+		if min > max {
+			report.add(rule, -1, offset, "%s %d initial size %d exceeds %d", what, i, min, max)
+		}
+		if limitMax != nil && *limitMax > max {
+			report.add(rule, -1, offset, "%s %d max size %d exceeds %d", what, i, *limitMax, max)
+		}
+	}
+	return nil
+}
+
+// lintCode walk the instructions of every function body
+func lintCode(section *wasmergo.WasmSection, importedFuncs int, options *LintOptions, report *LintReport) error {
+	r := wasmergo.NewWasmReader(section.Content)
+	count, err := r.U32()
+	if err != nil {
+		return err
+	}
+	for i := uint32(0); i < count; i++ {
+		size, err := r.U32()
+		if err != nil {
+			return err
+		}
+		start := r.Offset()
+		body, err := r.Bytes(size)
+		if err != nil {
+			return err
+		}
+		function := importedFuncs + int(i)
+		if err = lintFunction(body, section.Offset+start, function, options, report); err != nil {
+			return fmt.Errorf("function %d, %s", function, err.Error())
+		}
+	}
+	return nil
+}
+
+// lintFunction walk the instructions of one function body, offset is where body starts
+func lintFunction(body []byte, offset int, function int, options *LintOptions, report *LintReport) error {
+	r := wasmergo.NewWasmReader(body)
+
+	// locals, count and type
+	groups, err := r.U32()
+	if err != nil {
+		return err
+	}
+	for j := uint32(0); j < groups; j++ {
+		if _, err = r.U32(); err != nil {
+			return err
+		}
+		if _, err = r.Byte(); err != nil {
+			return err
+		}
+	}
+
+	for !r.EOF() {
+		at := offset + r.Offset()
+		op, err := r.Byte()
+		if err != nil {
+			return err
+		}
+		class, err := skipImmediates(r, op)
+		if err != nil {
+			return fmt.Errorf("opcode 0x%02x at offset %d, %s", op, at, err.Error())
+		}
+		switch class {
+		case instrFloat:
+			if options.ForbidFloat {
+				report.add(LintRuleFloat, function, at, "float instruction 0x%02x", op)
+			}
+		case instrVector, instrAtomic:
+			// the immediates are not decoded, the rest of the body can't be read
+			report.add(LintRuleInstruction, function, at, "%s instruction is not allowed", class)
+			return nil
+		}
+	}
+	return nil
+}
+
+// instrClass what the linter needs to know about an instruction
+type instrClass string
+
+const (
+	instrOther  instrClass = "other"
+	instrFloat  instrClass = "float"
+	instrVector instrClass = "simd"
+	instrAtomic instrClass = "atomic"
+)
+
+// skipImmediates read the immediates of op, see
+// https://webassembly.github.io/spec/core/binary/instructions.html
+func skipImmediates(r *wasmergo.WasmReader, op byte) (instrClass, error) {
+	var err error
+	switch {
+	// unreachable, nop, else, end, return, drop, select
+	case op == 0x00 || op == 0x01 || op == 0x05 || op == 0x0b || op == 0x0f || op == 0x1a || op == 0x1b:
+	// block, loop, if
+	case op >= 0x02 && op <= 0x04:
+		err = skipBlockType(r)
+	// br, br_if, call, return_call, local.*, global.*, table.get, table.set, ref.func
+	case op == 0x0c || op == 0x0d || op == 0x10 || op == 0x12 || (op >= 0x20 && op <= 0x26) || op == 0xd2:
+		_, err = r.U32()
+	case op == 0x0e: // br_table
+		var n uint32
+		if n, err = r.U32(); err == nil {
+			for i := uint32(0); i <= n && err == nil; i++ {
+				_, err = r.U32()
+			}
+		}
+	case op == 0x11 || op == 0x13: // call_indirect, return_call_indirect
+		if _, err = r.U32(); err == nil {
+			_, err = r.U32()
+		}
+	case op == 0x1c: // select t*
+		var n uint32
+		if n, err = r.U32(); err == nil {
+			_, err = r.Bytes(n)
+		}
+	case op >= 0x28 && op <= 0x3e: // loads and stores, memarg
+		if _, err = r.U32(); err == nil {
+			_, err = r.U32()
+		}
+		if op == 0x2a || op == 0x2b || op == 0x38 || op == 0x39 {
+			return instrFloat, err
+		}
+	case op == 0x3f || op == 0x40: // memory.size, memory.grow
+		_, err = r.Byte()
+	case op == 0x41:
+		err = r.SkipSigned(32)
+	case op == 0x42:
+		err = r.SkipSigned(64)
+	case op == 0x43:
+		_, err = r.Bytes(4)
+		return instrFloat, err
+	case op == 0x44:
+		_, err = r.Bytes(8)
+		return instrFloat, err
+	// float comparisons, arithmetic, and conversions from or to float
+	case (op >= 0x5b && op <= 0x66) || (op >= 0x8b && op <= 0xa6) || (op >= 0xa8 && op <= 0xab) ||
+		(op >= 0xae && op <= 0xbf):
+		return instrFloat, nil
+	// integer comparisons, arithmetic, wrap, extend
+	case op >= 0x45 && op <= 0xc4:
+	case op == 0xd0: // ref.null
+		_, err = r.Byte()
+	case op == 0xd1: // ref.is_null
+	case op == 0xfc:
+		return skipMiscImmediates(r)
+	case op == 0xfd:
+		return instrVector, nil
+	case op == 0xfe:
+		return instrAtomic, nil
+	default:
+		return instrOther, fmt.Errorf("unknown opcode")
+	}
+	return instrOther, err
+}
+
+// skipMiscImmediates read the 0xfc prefixed instructions
+func skipMiscImmediates(r *wasmergo.WasmReader) (instrClass, error) {
+	sub, err := r.U32()
+	if err != nil {
+		return instrOther, err
+	}
+	switch {
+	case sub <= 7: // trunc_sat
+		return instrFloat, nil
+	case sub == 8: // memory.init
+		if _, err = r.U32(); err == nil {
+			_, err = r.Byte()
+		}
+	case sub == 9 || sub == 13 || (sub >= 15 && sub <= 17): // data.drop, elem.drop, table.grow/size/fill
+		_, err = r.U32()
+	case sub == 10: // memory.copy
+		_, err = r.Bytes(2)
+	case sub == 11: // memory.fill
+		_, err = r.Byte()
+	case sub == 12 || sub == 14: // table.init, table.copy
+		if _, err = r.U32(); err == nil {
+			_, err = r.U32()
+		}
+	default:
+		return instrOther, fmt.Errorf("unknown 0xfc sub opcode %d", sub)
+	}
+	return instrOther, err
+}
+
+// skipBlockType empty, a value type or a type index
+func skipBlockType(r *wasmergo.WasmReader) error {
+	b, err := r.Peek()
+	if err != nil {
+		return err
+	}
+	switch b {
+	case 0x40, 0x7f, 0x7e, 0x7d, 0x7c, 0x7b, 0x70, 0x6f:
+		_, err = r.Byte()
+		return err
+	}
+	return r.SkipSigned(33)
+}
@@ -0,0 +1,85 @@
+package wavm
+
+import (
+	"testing"
+
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func lint(t *testing.T, byteCode []byte, options *LintOptions) *LintReport {
+	store := wasmergo.NewStore(wasmergo.NewDeterministicEngine())
+	module, err := wasmergo.NewModule(store, byteCode, nil)
+	assert.NoError(t, err)
+	report, err := LintContract(module, byteCode, options)
+	assert.NoError(t, err)
+	return report
+}
+
+func lintWat(t *testing.T, wat string, options *LintOptions) *LintReport {
+	byteCode, err := wasmergo.Wat2Wasm(wat)
+	assert.NoError(t, err)
+	return lint(t, byteCode, options)
+}
+
+func rules(report *LintReport) []string {
+	var rules []string
+	for _, issue := range report.Issues {
+		rules = append(rules, issue.Rule)
+	}
+	return rules
+}
+
+func TestLintRustCounter(t *testing.T) {
+	wasmBytes, _, _ := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+	report := lint(t, wasmBytes, nil)
+	assert.True(t, report.OK(), report.Err())
+}
+
+func TestLintHelloWorld(t *testing.T) {
+	wasmBytes, _, _ := prepareContract("./testdata/helloworld.wasm", t)
+	report := lint(t, wasmBytes, nil)
+	assert.False(t, report.OK())
+	assert.Contains(t, rules(report), LintRuleImport)
+	assert.Contains(t, rules(report), LintRuleExport)
+	assert.Error(t, report.Err())
+}
+
+func TestLintFloatStartAndMemory(t *testing.T) {
+	wat := `(module
+	(memory (export "memory") 1)
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "allocate") (param i32) (result i32) i32.const 0)
+	(func (export "deallocate") (param i32))
+	(func (export "half") (param f64) (result f64)
+		local.get 0
+		f64.const 2
+		f64.div))`
+	assert.True(t, lintWat(t, wat, nil).OK())
+
+	report := lintWat(t, wat, &LintOptions{ForbidFloat: true})
+	assert.Equal(t, []string{LintRuleFloat, LintRuleFloat}, rules(report))
+	assert.Equal(t, 3, report.Issues[0].Function)
+
+	// the WAT source itself is linted like its binary
+	assert.Equal(t, report, lint(t, []byte(wat), &LintOptions{ForbidFloat: true}))
+
+	wat = `(module
+	(memory (export "memory") 2048 4096)
+	(table 20000 funcref)
+	(func $init)
+	(start $init))`
+	report = lintWat(t, wat, &LintOptions{RequiredExports: []string{}})
+	assert.ElementsMatch(t, []string{LintRuleMemoryLimit, LintRuleMemoryLimit, LintRuleTableLimit, LintRuleStart},
+		rules(report))
+
+	report = lintWat(t, wat, &LintOptions{RequiredExports: []string{}, AllowStart: true,
+		MaxMemoryPages: 4096, MaxTableSize: 20000})
+	assert.True(t, report.OK(), report.Err())
+}
+
+func TestNewVmPoolWithLint(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/helloworld.wasm", t)
+	_, err := newVmPool(&contractId, wasmBytes, &PoolOptions{LintOptions: &LintOptions{}}, logger)
+	assert.Error(t, err)
+}
@@ -0,0 +1,335 @@
+package wavm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chainmaker.org/chainmaker/logger/v2"
+	"chainmaker.org/chainmaker/protocol/v2"
+	"github.com/jhyehuang/wasm-example/pkg/utils"
+	"github.com/jhyehuang/wasm-example/src/wavm/common"
+)
+
+const (
+	// a pool not used for this long is closed
+	defaultIdleTimeout = time.Hour * 24
+)
+
+// ErrManagerClosed the manager was closed, it creates no pool any more
+var ErrManagerClosed = errors.New("vm manager closed")
+
+// managedPool a runtime instance and the bookkeeping the manager needs to close it safely
+type managedPool struct {
+	runtime *RuntimeInstance
+	// lastUseTime, unix timestamp in ms
+	lastUseTime int64
+	// in-flight invokes, the pool is closed only after all of them return
+	inflight sync.WaitGroup
+}
+
+// poolKey identifies the pool of a contract name and version
+type poolKey struct {
+	name    string
+	version string
+}
+
+func poolKeyOf(contract *common.Contract) poolKey {
+	return poolKey{name: contract.Name, version: contract.Version}
+}
+
+// poolCreation a pool being compiled, the goroutines asking for the same
+// contract meanwhile wait for done instead of compiling it again
+type poolCreation struct {
+	done chan struct{}
+	mp   *managedPool
+	err  error
+}
+
+// Manager owns one vmPool per contract name and version, pools are created
+// lazily on first use, replaced on upgrade and closed when idle
+type Manager struct {
+	lock        sync.RWMutex
+	pools       map[poolKey]*managedPool
+	creating    map[poolKey]*poolCreation
+	closed      bool
+	options     *PoolOptions
+	idleTimeout time.Duration
+	closeC      chan struct{}
+	closeOnce   sync.Once
+	log         *logger.CMLogger
+}
+
+// NewManager create a manager whose pools all use options,
+// pools idle longer than idleTimeout are closed, zero uses the default
+func NewManager(options *PoolOptions, idleTimeout time.Duration, log *logger.CMLogger) *Manager {
+	if idleTimeout == 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+	m := &Manager{
+		pools:       make(map[poolKey]*managedPool),
+		creating:    make(map[poolKey]*poolCreation),
+		options:     options,
+		idleTimeout: idleTimeout,
+		closeC:      make(chan struct{}),
+		log:         log,
+	}
+	go m.startEvictLoop()
+	return m
+}
+
+// contractKey name:version of contract, for logs and metric labels, a contract name has no ':'
+func contractKey(contract *common.Contract) string {
+	return contract.Name + ":" + contract.Version
+}
+
+// NewRuntimeInstance get the runtime instance of contract, create its pool if not exist
+func (m *Manager) NewRuntimeInstance(contract *common.Contract, byteCode []byte) (*RuntimeInstance, error) {
+	mp, err := m.getOrCreatePool(contract, byteCode)
+	if err != nil {
+		return nil, err
+	}
+	return mp.runtime, nil
+}
+
+// Invoke contract method through the pool of contract, the pool is created from
+// byteCode on first use, if byteCode is nil it is loaded from txContext
+func (m *Manager) Invoke(contract *common.Contract, method string, byteCode []byte,
+	parameters map[string][]byte, txContext protocol.TxSimContext, gasUsed uint64) *common.ContractResult {
+
+	mp, err := m.acquire(contract, byteCode, txContext)
+	if err != nil {
+		return acquireFailedResult(err)
+	}
+	defer mp.inflight.Done()
+
+	return mp.runtime.Invoke(contract, method, byteCode, parameters, txContext, gasUsed)
+}
+
+// acquireFailedResult the result of an invoke which got no pool, a closed manager
+// serves no pool any more
+func acquireFailedResult(err error) *common.ContractResult {
+	code := ContractCodeInternal
+	if errors.Is(err, ErrManagerClosed) {
+		code = ContractCodePoolUnavailable
+	}
+	return &common.ContractResult{
+		Code:    code,
+		Message: err.Error(),
+	}
+}
+
+// InvokeWithContext same as Invoke, but gives up waiting for an instance when ctx is done
+func (m *Manager) InvokeWithContext(ctx context.Context, contract *common.Contract, method string,
+	byteCode []byte, parameters map[string][]byte, txContext protocol.TxSimContext,
+	gasUsed uint64) *common.ContractResult {
+
+	mp, err := m.acquire(contract, byteCode, txContext)
+	if err != nil {
+		return acquireFailedResult(err)
+	}
+	defer mp.inflight.Done()
+
+	return mp.runtime.InvokeWithContext(ctx, contract, method, byteCode, parameters, txContext, gasUsed)
+}
+
+// Upgrade create the pool of the new contract version and close the pools of other versions
+func (m *Manager) Upgrade(contract *common.Contract, byteCode []byte) error {
+	if _, err := m.getOrCreatePool(contract, byteCode); err != nil {
+		return err
+	}
+
+	key := poolKeyOf(contract)
+	m.lock.Lock()
+	var retired []*managedPool
+	for k, mp := range m.pools {
+		if k != key && k.name == contract.Name {
+			retired = append(retired, mp)
+			delete(m.pools, k)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, mp := range retired {
+		m.closePool(mp)
+	}
+	m.log.Infof("[%s] contract upgraded, %d old version pool(s) closed", contractKey(contract), len(retired))
+	return nil
+}
+
+// CloseRuntimeInstance close the pool of contract
+func (m *Manager) CloseRuntimeInstance(contract *common.Contract) {
+	key := poolKeyOf(contract)
+	m.lock.Lock()
+	mp, exists := m.pools[key]
+	delete(m.pools, key)
+	m.lock.Unlock()
+
+	if exists {
+		m.closePool(mp)
+	}
+}
+
+// Size return the count of pools
+func (m *Manager) Size() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+	return len(m.pools)
+}
+
+// Close all pools and stop evicting, invokes afterwards fail with ErrManagerClosed
+// and ContractCodePoolUnavailable
+func (m *Manager) Close() {
+	m.closeOnce.Do(func() {
+		close(m.closeC)
+	})
+
+	m.lock.Lock()
+	m.closed = true
+	pools := m.pools
+	m.pools = make(map[poolKey]*managedPool)
+	m.lock.Unlock()
+
+	for _, mp := range pools {
+		m.closePool(mp)
+	}
+}
+
+// acquire the pool of contract and mark an invoke in flight,
+// the caller must call inflight.Done when the invoke returns
+func (m *Manager) acquire(contract *common.Contract, byteCode []byte,
+	txContext protocol.TxSimContext) (*managedPool, error) {
+	key := poolKeyOf(contract)
+	for {
+		m.lock.RLock()
+		if m.closed {
+			m.lock.RUnlock()
+			return nil, fmt.Errorf("[%s] %w", contractKey(contract), ErrManagerClosed)
+		}
+		mp, exists := m.pools[key]
+		if exists {
+			// added under read lock, so a pool being removed never gets new invokes
+			mp.inflight.Add(1)
+			atomic.StoreInt64(&mp.lastUseTime, utils.CurrentTimeMillisSeconds())
+			m.lock.RUnlock()
+			return mp, nil
+		}
+		m.lock.RUnlock()
+
+		if byteCode == nil {
+			if txContext == nil {
+				return nil, fmt.Errorf("[%s] pool not exist and no byte code to create it", contractKey(contract))
+			}
+			var err error
+			byteCode, err = txContext.GetContractBytecode(contract.Name)
+			if err != nil {
+				return nil, fmt.Errorf("[%s] load byte code failed, %s", contractKey(contract), err.Error())
+			}
+		}
+		if _, err := m.getOrCreatePool(contract, byteCode); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getOrCreatePool get the pool of contract, or compile it without holding the lock,
+// concurrent calls for the same contract wait for a single compilation
+func (m *Manager) getOrCreatePool(contract *common.Contract, byteCode []byte) (*managedPool, error) {
+	key := poolKeyOf(contract)
+
+	m.lock.Lock()
+	if m.closed {
+		m.lock.Unlock()
+		return nil, fmt.Errorf("[%s] %w", contractKey(contract), ErrManagerClosed)
+	}
+	if mp, exists := m.pools[key]; exists {
+		m.lock.Unlock()
+		return mp, nil
+	}
+	if creation, exists := m.creating[key]; exists {
+		m.lock.Unlock()
+		<-creation.done
+		return creation.mp, creation.err
+	}
+	creation := &poolCreation{done: make(chan struct{})}
+	m.creating[key] = creation
+	m.lock.Unlock()
+	defer close(creation.done)
+
+	contractId := &common.Contract{
+		Name:    contract.Name,
+		Version: contract.Version,
+	}
+	pool, err := newVmPool(contractId, byteCode, m.options, m.log)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	delete(m.creating, key)
+	if err != nil {
+		creation.err = err
+		return nil, err
+	}
+	if m.closed {
+		// closed while compiling, the pool was never handed out
+		pool.close()
+		creation.err = fmt.Errorf("[%s] %w", contractKey(contract), ErrManagerClosed)
+		return nil, creation.err
+	}
+	creation.mp = &managedPool{
+		runtime: &RuntimeInstance{
+			pool: pool,
+			log:  m.log,
+		},
+		lastUseTime: utils.CurrentTimeMillisSeconds(),
+	}
+	m.pools[key] = creation.mp
+	m.log.Infof("[%s] vm pool created, total %d pool(s)", contractKey(contract), len(m.pools))
+	return creation.mp, nil
+}
+
+// closePool close the pool once the invokes in flight return,
+// the pool must have been removed from m.pools already
+func (m *Manager) closePool(mp *managedPool) {
+	go func() {
+		mp.inflight.Wait()
+		mp.runtime.pool.close()
+	}()
+}
+
+// startEvictLoop close the pools not used for idleTimeout
+func (m *Manager) startEvictLoop() {
+	ticker := time.NewTicker(m.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.evictIdle(m.idleTimeout)
+		case <-m.closeC:
+			return
+		}
+	}
+}
+
+// evictIdle close the pools not used for idleTimeout, return how many are closed
+func (m *Manager) evictIdle(idleTimeout time.Duration) int {
+	deadline := utils.CurrentTimeMillisSeconds() - idleTimeout.Milliseconds()
+
+	m.lock.Lock()
+	var idle []*managedPool
+	for k, mp := range m.pools {
+		if atomic.LoadInt64(&mp.lastUseTime) < deadline {
+			idle = append(idle, mp)
+			delete(m.pools, k)
+			m.log.Infof("[%s] vm pool idle for %v, close it", contractKey(mp.runtime.pool.contractId), idleTimeout)
+		}
+	}
+	m.lock.Unlock()
+
+	for _, mp := range idle {
+		m.closePool(mp)
+	}
+	return len(idle)
+}
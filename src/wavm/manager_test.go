@@ -0,0 +1,121 @@
+package wavm
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jhyehuang/wasm-example/src/wavm/common"
+	"github.com/jhyehuang/wasm-example/src/wavm/txsim"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagerInvoke(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	manager := NewManager(nil, 0, logger)
+	defer manager.Close()
+
+	parameters := make(map[string][]byte)
+	parameters["key"] = []byte("test_key")
+	fillingBaseParams(parameters)
+
+	store := txsim.NewStore()
+	for i := 0; i < 2; i++ {
+		txContext := newTxSimContext(store)
+		ret := manager.Invoke(&contractId, "increase", wasmBytes, parameters, txContext, 0)
+		assert.Equal(t, ContractCodeSuccess, ret.Code, ret.Message)
+		txContext.Commit()
+	}
+	assert.Equal(t, 1, manager.Size())
+
+	key := contractKey(&contractId)
+	assert.True(t, invokeCounter.Value(key, "increase", "0") >= 2)
+	assert.True(t, invokeHistogram.Count(key, "increase") >= 2)
+	assert.True(t, invokeGasCounter.Value(key, "increase") > 0)
+	assert.True(t, poolSizeGauge.Value(key) > 0)
+}
+
+func TestManagerInvokeWithoutByteCode(t *testing.T) {
+	_, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	manager := NewManager(nil, 0, logger)
+	defer manager.Close()
+
+	ret := manager.Invoke(&contractId, "increase", nil, map[string][]byte{}, nil, 0)
+	assert.Equal(t, ContractCodeInternal, ret.Code)
+	assert.Equal(t, 0, manager.Size())
+}
+
+func TestManagerUpgradeAndEvict(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	manager := NewManager(nil, 0, logger)
+	defer manager.Close()
+
+	_, err := manager.NewRuntimeInstance(&contractId, wasmBytes)
+	assert.NoError(t, err)
+
+	newVersion := &common.Contract{
+		Name:    contractId.Name,
+		Version: "2.0.0",
+	}
+	assert.NoError(t, manager.Upgrade(newVersion, wasmBytes))
+	assert.Equal(t, 1, manager.Size())
+
+	assert.Equal(t, 0, manager.evictIdle(time.Hour))
+	time.Sleep(time.Millisecond * 10)
+	assert.Equal(t, 1, manager.evictIdle(time.Millisecond))
+	assert.Equal(t, 0, manager.Size())
+}
+
+func TestContractKey(t *testing.T) {
+	// name_version would make both a_b_c
+	a := &common.Contract{Name: "a_b", Version: "c"}
+	b := &common.Contract{Name: "a", Version: "b_c"}
+	assert.NotEqual(t, poolKeyOf(a), poolKeyOf(b))
+	assert.NotEqual(t, contractKey(a), contractKey(b))
+}
+
+func TestManagerConcurrentCreateAndClose(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	manager := NewManager(nil, 0, logger)
+
+	// the pool is compiled once, every caller gets it
+	runtimes := make(chan *RuntimeInstance, 4)
+	for i := 0; i < cap(runtimes); i++ {
+		go func() {
+			runtime, err := manager.NewRuntimeInstance(&contractId, wasmBytes)
+			assert.NoError(t, err)
+			runtimes <- runtime
+		}()
+	}
+	first := <-runtimes
+	for i := 1; i < cap(runtimes); i++ {
+		assert.True(t, first == <-runtimes)
+	}
+	assert.Equal(t, 1, manager.Size())
+
+	manager.Close()
+	ret := manager.Invoke(&contractId, "increase", wasmBytes, map[string][]byte{}, nil, 0)
+	assert.Equal(t, ContractCodePoolUnavailable, ret.Code)
+	assert.Contains(t, ret.Message, ErrManagerClosed.Error())
+	assert.Equal(t, 0, manager.Size())
+}
+
+func TestManagerInvokeAfterClose(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	manager := NewManager(nil, 0, logger)
+	manager.Close()
+
+	ret := manager.Invoke(&contractId, "increase", wasmBytes, map[string][]byte{}, nil, 0)
+	assert.Equal(t, ContractCodePoolUnavailable, ret.Code)
+	assert.Contains(t, ret.Message, ErrManagerClosed.Error())
+
+	ret = manager.InvokeWithContext(context.Background(), &contractId, "increase", wasmBytes,
+		map[string][]byte{}, nil, 0)
+	assert.Equal(t, ContractCodePoolUnavailable, ret.Code)
+	assert.Equal(t, 0, manager.Size())
+}
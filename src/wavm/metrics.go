@@ -0,0 +1,47 @@
+package wavm
+
+import (
+	"github.com/jhyehuang/wasm-example/pkg/metrics"
+)
+
+// labels: contract is name:version, see contractKey, method is the contract method invoked
+var (
+	poolSizeGauge = metrics.NewGaugeVec("wavm_pool_size",
+		"Number of wasmer instances owned by the vm pool.", "contract")
+	poolWaitHistogram = metrics.NewHistogramVec("wavm_pool_get_instance_seconds",
+		"Time spent waiting for an instance from the vm pool.", nil, "contract")
+	poolGrowCounter = metrics.NewCounterVec("wavm_pool_grow_instances_total",
+		"Number of instances the vm pool grew by.", "contract")
+	poolShrinkCounter = metrics.NewCounterVec("wavm_pool_shrink_instances_total",
+		"Number of instances the vm pool shrank by.", "contract")
+	poolDiscardCounter = metrics.NewCounterVec("wavm_pool_discard_instances_total",
+		"Number of instances discarded after errors or interrupts.", "contract")
+
+	invokeHistogram = metrics.NewHistogramVec("wavm_invoke_duration_seconds",
+		"Time spent in Invoke, including waiting for an instance.", nil, "contract", "method")
+	invokeCounter = metrics.NewCounterVec("wavm_invoke_total",
+		"Number of invokes by result code.", "contract", "method", "code")
+	invokeGasCounter = metrics.NewCounterVec("wavm_invoke_gas_used_total",
+		"Gas used by invokes.", "contract", "method")
+	invokeTrapCounter = metrics.NewCounterVec("wavm_invoke_traps_total",
+		"Number of invokes that trapped, out of gas included.", "contract", "method")
+)
+
+func init() {
+	metrics.MustRegister(poolSizeGauge, poolWaitHistogram, poolGrowCounter, poolShrinkCounter,
+		poolDiscardCounter, invokeHistogram, invokeCounter, invokeGasCounter, invokeTrapCounter)
+}
+
+// deleteContractMetrics delete every series of the contract key, when its pool is closed
+func deleteContractMetrics(key string) {
+	labels := map[string]string{"contract": key}
+	poolSizeGauge.DeletePartialMatch(labels)
+	poolWaitHistogram.DeletePartialMatch(labels)
+	poolGrowCounter.DeletePartialMatch(labels)
+	poolShrinkCounter.DeletePartialMatch(labels)
+	poolDiscardCounter.DeletePartialMatch(labels)
+	invokeHistogram.DeletePartialMatch(labels)
+	invokeCounter.DeletePartialMatch(labels)
+	invokeGasCounter.DeletePartialMatch(labels)
+	invokeTrapCounter.DeletePartialMatch(labels)
+}
@@ -0,0 +1,195 @@
+package wavm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chainmaker.org/chainmaker/logger/v2"
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+)
+
+const (
+	// every artifact file starts with this magic, then the sha256 of the payload
+	moduleCacheMagic = "WAVMMOD1"
+	// artifact file suffix, the name is the cache key
+	moduleCacheSuffix = ".wasmer"
+	// the max count of artifacts kept in the cache directory
+	defaultModuleCacheMaxEntries = 1000
+)
+
+// ModuleCache a content-addressed on-disk cache of compiled modules, the key covers
+// the byte code, the profile of the engine compiling it and the wasmer version, the
+// profile covers the engine config down to the metering cost model and gas limit, so an
+// artifact is never loaded by a runtime that did not produce it; every artifact carries the
+// checksum of its payload and a corrupted one is removed rather than loaded,
+// the least recently used artifacts are evicted beyond maxEntries
+type ModuleCache struct {
+	lock       sync.Mutex
+	dir        string
+	maxEntries int
+	log        *logger.CMLogger
+}
+
+// NewModuleCache create the cache in dir, maxEntries zero uses the default
+func NewModuleCache(dir string, maxEntries int, log *logger.CMLogger) (*ModuleCache, error) {
+	if maxEntries == 0 {
+		maxEntries = defaultModuleCacheMaxEntries
+	}
+	if maxEntries < 0 {
+		return nil, fmt.Errorf("module cache max entries %d should be positive", maxEntries)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("create module cache dir %s failed, %s", dir, err.Error())
+	}
+	return &ModuleCache{
+		dir:        dir,
+		maxEntries: maxEntries,
+		log:        log,
+	}, nil
+}
+
+// key of the artifact compiled from byteCode by the engine of store
+func (c *ModuleCache) key(store *wasmergo.Store, byteCode []byte) string {
+	codeHash := sha256.Sum256(byteCode)
+	profile := store.Engine.Profile() + "/" + wasmergo.Version()
+	keyHash := sha256.Sum256([]byte(hex.EncodeToString(codeHash[:]) + "/" + profile))
+	return hex.EncodeToString(keyHash[:])
+}
+
+func (c *ModuleCache) path(key string) string {
+	return filepath.Join(c.dir, key+moduleCacheSuffix)
+}
+
+// Load the module compiled from byteCode into store, return false if it is not cached
+// or the artifact is corrupted, the corrupted one is removed
+func (c *ModuleCache) Load(store *wasmergo.Store, byteCode []byte) (*wasmergo.Module, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	path := c.path(c.key(store, byteCode))
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			c.log.Warnf("module cache read %s failed, %s", path, err.Error())
+		}
+		return nil, false
+	}
+
+	payload, err := decodeArtifact(content)
+	if err != nil {
+		c.log.Warnf("module cache remove corrupted %s, %s", path, err.Error())
+		c.removeFile(path)
+		return nil, false
+	}
+
+	module, err := wasmergo.DeserializeModuleWithSymbols(store, payload, byteCode)
+	if err != nil {
+		c.log.Warnf("module cache remove undeserializable %s, %s", path, err.Error())
+		c.removeFile(path)
+		return nil, false
+	}
+
+	// the mtime orders artifacts for eviction
+	now := time.Now()
+	if err = os.Chtimes(path, now, now); err != nil {
+		c.log.Warnf("module cache touch %s failed, %s", path, err.Error())
+	}
+	return module, true
+}
+
+// Store the module compiled from byteCode into store, then evict beyond maxEntries
+func (c *ModuleCache) Store(store *wasmergo.Store, module *wasmergo.Module, byteCode []byte) error {
+	payload, err := module.Serialize()
+	if err != nil {
+		return fmt.Errorf("serialize module failed, %s", err.Error())
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	// write to a temp file then rename, a crash never leaves a half written artifact
+	path := c.path(c.key(store, byteCode))
+	tmpFile, err := ioutil.TempFile(c.dir, "tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temp file failed, %s", err.Error())
+	}
+	_, err = tmpFile.Write(encodeArtifact(payload))
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpFile.Name(), path)
+	}
+	if err != nil {
+		c.removeFile(tmpFile.Name())
+		return fmt.Errorf("write module cache %s failed, %s", path, err.Error())
+	}
+
+	c.evict()
+	return nil
+}
+
+// evict the least recently used artifacts beyond maxEntries, lock must be held
+func (c *ModuleCache) evict() {
+	entries, err := ioutil.ReadDir(c.dir)
+	if err != nil {
+		c.log.Warnf("module cache list %s failed, %s", c.dir, err.Error())
+		return
+	}
+
+	var artifacts []os.FileInfo
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), moduleCacheSuffix) {
+			artifacts = append(artifacts, entry)
+		}
+	}
+	if len(artifacts) <= c.maxEntries {
+		return
+	}
+
+	sort.Slice(artifacts, func(i, j int) bool {
+		return artifacts[i].ModTime().Before(artifacts[j].ModTime())
+	})
+	for _, artifact := range artifacts[:len(artifacts)-c.maxEntries] {
+		c.removeFile(filepath.Join(c.dir, artifact.Name()))
+	}
+	c.log.Infof("module cache evicted %d artifact(s)", len(artifacts)-c.maxEntries)
+}
+
+func (c *ModuleCache) removeFile(path string) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		c.log.Warnf("module cache remove %s failed, %s", path, err.Error())
+	}
+}
+
+// encodeArtifact prefix payload with magic and checksum
+func encodeArtifact(payload []byte) []byte {
+	checksum := sha256.Sum256(payload)
+	content := make([]byte, 0, len(moduleCacheMagic)+len(checksum)+len(payload))
+	content = append(content, moduleCacheMagic...)
+	content = append(content, checksum[:]...)
+	return append(content, payload...)
+}
+
+// decodeArtifact check magic and checksum, return the payload
+func decodeArtifact(content []byte) ([]byte, error) {
+	headerLen := len(moduleCacheMagic) + sha256.Size
+	if len(content) < headerLen || string(content[:len(moduleCacheMagic)]) != moduleCacheMagic {
+		return nil, fmt.Errorf("bad artifact header")
+	}
+	payload := content[headerLen:]
+	checksum := sha256.Sum256(payload)
+	if !bytes.Equal(checksum[:], content[len(moduleCacheMagic):headerLen]) {
+		return nil, fmt.Errorf("artifact checksum mismatch")
+	}
+	return payload, nil
+}
@@ -0,0 +1,88 @@
+package wavm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtifactEncoding(t *testing.T) {
+	content := encodeArtifact([]byte("payload"))
+	payload, err := decodeArtifact(content)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+
+	content[len(content)-1] ^= 0xff
+	_, err = decodeArtifact(content)
+	assert.Error(t, err)
+
+	_, err = decodeArtifact([]byte("short"))
+	assert.Error(t, err)
+}
+
+func TestModuleCache(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	cache, err := NewModuleCache(t.TempDir(), 1, logger)
+	assert.NoError(t, err)
+
+	store := wasmergo.NewStore(wasmergo.NewDeterministicEngine())
+	_, ok := cache.Load(store, wasmBytes)
+	assert.False(t, ok)
+
+	module, err := compileModule(store, &contractId, wasmBytes, cache, logger)
+	assert.NoError(t, err)
+	assert.NotNil(t, module)
+
+	cached, ok := cache.Load(store, wasmBytes)
+	assert.True(t, ok)
+	assert.Equal(t, len(module.Exports()), len(cached.Exports()))
+
+	// a corrupted artifact is removed instead of loaded
+	path := cache.path(cache.key(store, wasmBytes))
+	assert.NoError(t, ioutil.WriteFile(path, []byte("garbage"), 0644))
+	_, ok = cache.Load(store, wasmBytes)
+	assert.False(t, ok)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+
+	// only the most recent artifact survives with max entries 1
+	helloBytes, _, _ := prepareContract("./testdata/helloworld.wasm", t)
+	assert.NoError(t, cache.Store(store, module, wasmBytes))
+	helloModule, err := wasmergo.NewModule(store, helloBytes, logger)
+	assert.NoError(t, err)
+	assert.NoError(t, cache.Store(store, helloModule, helloBytes))
+	artifacts, err := filepath.Glob(filepath.Join(cache.dir, "*"+moduleCacheSuffix))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{cache.path(cache.key(store, helloBytes))}, artifacts)
+
+	// another metering is another key
+	config := wasmergo.NewDeterministicConfig().PushMeteringMiddleware(&wasmergo.Metering{InitialLimit: 1000, DefaultCost: 1})
+	meteredEngine, err := wasmergo.NewEngineWithConfig(config)
+	assert.NoError(t, err)
+	meteredStore := wasmergo.NewStore(meteredEngine)
+	assert.NotEqual(t, cache.key(store, helloBytes), cache.key(meteredStore, helloBytes))
+	_, ok = cache.Load(meteredStore, helloBytes)
+	assert.False(t, ok)
+}
+
+func TestNewVmPoolWithModuleCache(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	cache, err := NewModuleCache(t.TempDir(), 0, logger)
+	assert.NoError(t, err)
+
+	var store *wasmergo.Store
+	for i := 0; i < 2; i++ {
+		pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{ModuleCache: cache}, logger)
+		assert.NoError(t, err)
+		store = pool.store
+		pool.close()
+	}
+	_, err = os.Stat(cache.path(cache.key(store, wasmBytes)))
+	assert.NoError(t, err)
+}
@@ -3,10 +3,43 @@ package wavm
 import (
 	"chainmaker.org/chainmaker/logger/v2"
 	"chainmaker.org/chainmaker/protocol/v2"
+	"context"
+	"errors"
 	"fmt"
+	"github.com/jhyehuang/wasm-example/pkg/log"
 	"github.com/jhyehuang/wasm-example/pkg/utils"
 	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
 	"github.com/jhyehuang/wasm-example/src/wavm/common"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// the codes of a ContractResult, see common.ContractResultCode
+const (
+	// ContractCodeSuccess contract returned successfully
+	ContractCodeSuccess = uint32(common.ContractResultCode_OK)
+	// ContractCodeInternal vm failed to run the contract for another reason, e.g. a panic
+	ContractCodeInternal = uint32(common.ContractResultCode_INTERNAL)
+	// ContractCodeContractError contract ran to the end but reported a business error
+	ContractCodeContractError = uint32(common.ContractResultCode_CONTRACT_ERROR)
+	// ContractCodeTimeout contract was interrupted because the invoke deadline expired
+	ContractCodeTimeout = uint32(common.ContractResultCode_TIMEOUT)
+	// ContractCodeOutOfGas contract ran out of gas
+	ContractCodeOutOfGas = uint32(common.ContractResultCode_OUT_OF_GAS)
+	// ContractCodeTrap contract trapped, e.g. unreachable, out of bounds memory access
+	ContractCodeTrap = uint32(common.ContractResultCode_TRAP)
+	// ContractCodeMissingMethod contract doesn't export the method
+	ContractCodeMissingMethod = uint32(common.ContractResultCode_MISSING_METHOD)
+	// ContractCodePoolUnavailable no instance of the contract was available
+	ContractCodePoolUnavailable = uint32(common.ContractResultCode_POOL_UNAVAILABLE)
+
+	// ContractCodeFail vm failed to run the contract
+	//
+	// Deprecated: failures are classified, use ContractCodeInternal or the other codes
+	ContractCodeFail = ContractCodeInternal
 )
 
 // wrappedInstance wraps instance with id and other info
@@ -15,12 +48,16 @@ type wrappedInstance struct {
 	id string
 	// wasmergo instance provided by wasmer
 	wasmInstance *wasmergo.Instance
+	// exports the host calls, resolved when the instance is created
+	exports *contractExports
 	// lastUseTime, unix timestamp in ms
 	lastUseTime int64
 	// createTime, unix timestamp in ms
 	createTime int64
 	// errCount, current instance invoke method error count
 	errCount int32
+	// interrupted, the deadline expired while running, never reuse it
+	interrupted bool
 }
 
 // vmPool, each contract has a vm pool providing multiple vm instances to call
@@ -31,6 +68,8 @@ type vmPool struct {
 	byteCode   []byte
 	store      *wasmergo.Store
 	module     *wasmergo.Module
+	// sizing and policy of the pool
+	options *PoolOptions
 	// wasmergo instance pool
 	instances chan *wrappedInstance
 	// current instance size in pool
@@ -62,9 +101,33 @@ func (r *RuntimeInstance) Pool() *vmPool {
 	return r.pool
 }
 
-// Invoke contract by call vm, implement protocol.RuntimeInstance
+// Invoke contract by call vm, implement protocol.RuntimeInstance,
+// waits at most PoolOptions.GetInstanceTimeout for an instance
+// and then runs the contract at most PoolOptions.ExecuteTimeout
 func (r *RuntimeInstance) Invoke(contract *common.Contract, method string, byteCode []byte,
-	parameters map[string][]byte, gasUsed uint64) (
+	parameters map[string][]byte, txContext protocol.TxSimContext, gasUsed uint64) (
+	contractResult *common.ContractResult) {
+
+	return r.invoke(context.Background(), r.pool.options.ExecuteTimeout, contract, method, parameters, txContext,
+		gasUsed)
+}
+
+// InvokeWithContext same as Invoke, but the deadline of ctx bounds the whole call:
+// waiting for an instance gives up when ctx is done or the pool is closed, the
+// GetInstanceError is reported in the contract result as ContractCodePoolUnavailable, and a contract still
+// running when ctx is done is interrupted, reported as ContractCodeTimeout
+// and its instance discarded
+func (r *RuntimeInstance) InvokeWithContext(ctx context.Context, contract *common.Contract, method string,
+	byteCode []byte, parameters map[string][]byte, txContext protocol.TxSimContext, gasUsed uint64) (
+	contractResult *common.ContractResult) {
+
+	return r.invoke(ctx, 0, contract, method, parameters, txContext, gasUsed)
+}
+
+// invoke run the contract until ctx is done, and at most executeTimeout once it has an instance
+// if executeTimeout is positive
+func (r *RuntimeInstance) invoke(ctx context.Context, executeTimeout time.Duration, contract *common.Contract,
+	method string, parameters map[string][]byte, txContext protocol.TxSimContext, gasUsed uint64) (
 	contractResult *common.ContractResult) {
 
 	startTime := utils.CurrentTimeMillisSeconds()
@@ -72,31 +135,62 @@ func (r *RuntimeInstance) Invoke(contract *common.Contract, method string, byteC
 
 	// set default return value
 	contractResult = &common.ContractResult{
-		Code:    uint32(0),
+		Code:    ContractCodeSuccess,
 		Result:  nil,
 		Message: "",
 	}
 
 	var instanceInfo *wrappedInstance
+	var wd *watchdog
+	// trapped, the contract trapped or ran out of gas, timeouts and other errors excluded
+	var trapped bool
 	defer func() {
 		endTime := utils.CurrentTimeMillisSeconds()
 		logStr := fmt.Sprintf(" used time %d", endTime-startTime)
 		r.log.Debugf(logStr)
 		panicErr := recover()
 		if panicErr != nil {
-			contractResult.Code = 1
+			contractResult.Code = ContractCodeInternal
+			contractResult.Result = nil
 			contractResult.Message = fmt.Sprint(panicErr)
 			if instanceInfo != nil {
 				instanceInfo.errCount++
 			}
+			if wd != nil && wd.stop() {
+				instanceInfo.interrupted = true
+				contractResult.Code = ContractCodeTimeout
+			}
+		}
+		observeInvoke(contract, method, contractResult, trapped, endTime-startTime)
+		// reverted last, a pool closing once its instances are back deletes the series just observed
+		if instanceInfo != nil {
+			r.pool.RevertInstance(instanceInfo)
 		}
 	}()
 
-	instanceInfo = r.pool.GetInstance()
-	defer r.pool.RevertInstance(instanceInfo)
+	waitCtx, cancel := context.WithTimeout(ctx, r.pool.options.GetInstanceTimeout)
+	defer cancel()
+	instanceInfo, err := r.pool.GetInstance(waitCtx)
+	if err != nil {
+		r.log.Errorf("contract invoke failed, %s", err.Error())
+		contractResult.Code = ContractCodePoolUnavailable
+		contractResult.Message = err.Error()
+		return
+	}
+	// the execute deadline starts once the instance is acquired
+	if executeTimeout > 0 {
+		var cancelExecute context.CancelFunc
+		ctx, cancelExecute = context.WithTimeout(ctx, executeTimeout)
+		defer cancelExecute()
+	}
 
 	instance := instanceInfo.wasmInstance
-	instance.SetGasLimit(protocol.GasLimit - gasUsed)
+	if err = instance.SetGasLimit(protocol.GasLimit - gasUsed); err != nil {
+		contractResult.Code = ContractCodeInternal
+		contractResult.Message = fmt.Sprintf("contract invoke failed, set gas limit, %s", err.Error())
+		r.log.Errorf(contractResult.Message)
+		return
+	}
 
 	var sc = NewSimContext(method, r.log, "")
 	defer sc.removeCtxPointer()
@@ -104,24 +198,54 @@ func (r *RuntimeInstance) Invoke(contract *common.Contract, method string, byteC
 	sc.ContractResult = contractResult
 	sc.parameters = parameters
 	sc.Instance = instance
+	sc.exports = instanceInfo.exports
+	sc.TxSimContext = txContext
+	sc.ctx = ctx
 
-	err := sc.CallMethod(instance)
+	wd = startWatchdog(ctx, instance)
+	err = sc.CallMethod(instance)
+	if wd.stop() {
+		instanceInfo.interrupted = true
+		contractResult.Code = ContractCodeTimeout
+		contractResult.Result = nil
+		// an interrupted contract has no points left, it is charged all of its gas
+		if remaining, gasErr := instance.GetGasRemaining(); gasErr == nil {
+			contractResult.GasUsed = protocol.GasLimit - remaining
+		}
+		contractResult.Message = fmt.Sprintf("contract invoke failed, interrupted, %s, tx: %s",
+			ctx.Err().Error(), sc.txId())
+		r.log.Errorf(contractResult.Message)
+		return
+	}
 	if err != nil {
-		r.log.Errorf("contract invoke failed, %s, tx: %s", err)
+		r.log.Errorf("contract invoke failed, %s, tx: %s", errorMessage(err), sc.txId())
 	}
 
-	// gas Log
-	gas := protocol.GasLimit - instance.GetGasRemaining()
-	if instance.GetGasRemaining() <= 0 {
+	// gas Log, an instance out of gas has no points left, whether or not the
+	// contract reported the trap
+	remaining, gasErr := instance.GetGasRemaining()
+	exhausted, exhaustedErr := instance.PointsExhausted()
+	if gasErr == nil {
+		gasErr = exhaustedErr
+	}
+	if gasErr != nil && err == nil {
+		err = fmt.Errorf("read remaining gas failed, %s", gasErr.Error())
+	}
+	gas := protocol.GasLimit - remaining
+	code := resultCode(err)
+	if exhausted || trapKind(err) == wasmergo.TrapOutOfGas {
+		code = ContractCodeOutOfGas
 		err = fmt.Errorf("contract invoke failed, out of gas %d/%d, tx: %s", gas, int64(protocol.GasLimit),
-			"")
+			sc.txId())
 	}
+	trapped = code == ContractCodeTrap || code == ContractCodeOutOfGas
 	logStr += fmt.Sprintf("used gas %d ", gas)
 	contractResult.GasUsed = gas
 
 	if err != nil {
-		contractResult.Code = 1
-		msg := fmt.Sprintf("contract invoke failed, %s", err.Error())
+		contractResult.Code = code
+		contractResult.Result = nil
+		msg := fmt.Sprintf("contract invoke failed, %s", errorMessage(err))
 		r.log.Errorf(msg)
 		contractResult.Message = msg
 		if method != "init_contract" {
@@ -129,6 +253,129 @@ func (r *RuntimeInstance) Invoke(contract *common.Contract, method string, byteC
 		}
 		return
 	}
+
+	// the contract itself reported an error, the instance is still healthy
+	if contractResult.Code == ContractCodeContractError {
+		r.log.Warnf("contract [%s] method [%s] returned error: %s", contract.Name, method, contractResult.Message)
+		return
+	}
 	contractResult.GasUsed = gas
 	return
 }
+
+// observeInvoke record the latency, result code, gas and trap of an invoke
+func observeInvoke(contract *common.Contract, method string, contractResult *common.ContractResult,
+	trapped bool, elapsedMS int64) {
+	key := contractKey(contract)
+	invokeHistogram.Observe(float64(elapsedMS)/1000, key, method)
+	invokeCounter.Inc(key, method, strconv.Itoa(int(contractResult.Code)))
+	invokeGasCounter.Add(float64(contractResult.GasUsed), key, method)
+	if trapped {
+		invokeTrapCounter.Inc(key, method)
+	}
+}
+
+// resultCode classify the error of a contract call, nil is a success
+func resultCode(err error) uint32 {
+	var trapErr *wasmergo.TrapError
+	var missingErr *missingMethodError
+	switch {
+	case err == nil:
+		return ContractCodeSuccess
+	case errors.As(err, &missingErr):
+		return ContractCodeMissingMethod
+	case errors.As(err, &trapErr):
+		if trapErr.Kind() == wasmergo.TrapOutOfGas {
+			return ContractCodeOutOfGas
+		}
+		return ContractCodeTrap
+	default:
+		return ContractCodeInternal
+	}
+}
+
+// errorMessage describe err for the contract result, a trap with its kind and
+// the functions it went through, the innermost first
+func errorMessage(err error) string {
+	var trapErr *wasmergo.TrapError
+	if !errors.As(err, &trapErr) {
+		return err.Error()
+	}
+	frames := make([]string, 0, len(trapErr.Trace()))
+	for _, frame := range trapErr.Trace() {
+		frames = append(frames, frame.String())
+	}
+	return fmt.Sprintf("%s trap: %s, backtrace: %s", trapErr.Kind(), trapErr.Error(), strings.Join(frames, " <- "))
+}
+
+// trapKind return the kind of the trap err is, TrapOther if it is not a trap
+func trapKind(err error) wasmergo.TrapKind {
+	var trapErr *wasmergo.TrapError
+	if !errors.As(err, &trapErr) {
+		return wasmergo.TrapOther
+	}
+	return trapErr.Kind()
+}
+
+// interruptInterval how often the watchdog interrupts the instance again
+// until the call returns, an interrupt racing with the metering of the
+// contract may be lost
+const interruptInterval = time.Millisecond
+
+// watchdog interrupts an instance when ctx is done before stop is called
+type watchdog struct {
+	stopC   chan struct{}
+	stopped chan struct{}
+	once    sync.Once
+	// 1 while the call runs, whoever of stop and ctx swaps it to 0 first decides
+	// whether the call completed or was interrupted
+	running int32
+	fired   bool
+}
+
+// startWatchdog watch ctx until stop is called
+func startWatchdog(ctx context.Context, instance *wasmergo.Instance) *watchdog {
+	wd := &watchdog{
+		stopC:   make(chan struct{}),
+		stopped: make(chan struct{}),
+		running: 1,
+	}
+	go func() {
+		defer close(wd.stopped)
+		select {
+		case <-ctx.Done():
+			// the call may have returned while ctx fired, it is not interrupted then
+			if !atomic.CompareAndSwapInt32(&wd.running, 1, 0) {
+				return
+			}
+			wd.fired = true
+			if err := instance.Interrupt(); err != nil {
+				log.Errorf("interrupt instance failed, %s", err.Error())
+				return
+			}
+			ticker := time.NewTicker(interruptInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					_ = instance.Interrupt()
+				case <-wd.stopC:
+					return
+				}
+			}
+		case <-wd.stopC:
+		}
+	}()
+	return wd
+}
+
+// stop watching and report whether the instance was interrupted while the call
+// was still running, safe to call more than once
+func (wd *watchdog) stop() bool {
+	wd.once.Do(func() {
+		atomic.CompareAndSwapInt32(&wd.running, 1, 0)
+		close(wd.stopC)
+	})
+	<-wd.stopped
+	return wd.fired
+}
@@ -9,9 +9,14 @@ package wavm
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"github.com/jhyehuang/wasm-example/pkg/log"
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+	"github.com/jhyehuang/wasm-example/src/wavm/txsim"
+	"strings"
 	"testing"
+	"time"
 
 	"chainmaker.org/chainmaker/protocol/v2"
 )
@@ -36,9 +41,9 @@ func readWriteSet(txSimContext protocol.TxSimContext) ([]byte, error) {
 // TestInvoke comment at next version
 func TestInvoke(t *testing.T) {
 
-	wasmBytes, contractId, logger := prepareContract("./testdata/helloworld.wasm", t)
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
 
-	vmPool, err := newVmPool(&contractId, wasmBytes, logger)
+	vmPool, err := newVmPool(&contractId, wasmBytes, nil, logger)
 	if err != nil {
 		t.Fatalf("create vmPool error: %v", err)
 	}
@@ -57,10 +62,189 @@ func TestInvoke(t *testing.T) {
 	fillingBaseParams(parameters)
 
 	// 测试一次调用结果是否正确
-	ret := runtimeInst.Invoke(&contractId, "increase", wasmBytes, parameters, 0)
+	ret := runtimeInst.Invoke(&contractId, "increase", wasmBytes, parameters, nil, 0)
 	log.Infof("ret = %v", ret)
 	// 测试第二次调用结果是否正确
-	runtimeInst.Invoke(&contractId, "increase", wasmBytes, parameters, 0)
+	runtimeInst.Invoke(&contractId, "increase", wasmBytes, parameters, nil, 0)
 	log.Infof("ret = %v", ret)
 
 }
+
+// TestInvokeRustCounter run a ChainMaker rust contract against an in-memory TxSimContext
+func TestInvokeRustCounter(t *testing.T) {
+
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	vmPool, err := newVmPool(&contractId, wasmBytes, nil, logger)
+	if err != nil {
+		t.Fatalf("create vmPool error: %v", err)
+	}
+
+	defer func() {
+		vmPool.close()
+	}()
+
+	runtimeInst := RuntimeInstance{
+		pool: vmPool,
+		log:  logger,
+	}
+
+	parameters := make(map[string][]byte)
+	parameters["key"] = []byte("test_key")
+	fillingBaseParams(parameters)
+
+	store := txsim.NewStore()
+	for i := 0; i < 2; i++ {
+		txContext := newTxSimContext(store)
+		ret := runtimeInst.Invoke(&contractId, "increase", wasmBytes, parameters, txContext, 0)
+		log.Infof("ret = %v", ret)
+		if ret.Code != ContractCodeSuccess {
+			t.Fatalf("invoke increase failed: %s", ret.Message)
+		}
+
+		if _, err = readWriteSet(txContext); err != nil {
+			t.Fatalf("invoke increase failed: %v", err)
+		}
+		txContext.Commit()
+	}
+}
+
+// spinWat a contract whose `spin` method never returns
+const spinWat = `(module
+	(memory (export "memory") 1)
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "allocate") (param i32) (result i32) i32.const 0)
+	(func (export "deallocate") (param i32))
+	(func (export "spin") (loop br 0)))`
+
+// TestInvokeTimeout an endless loop is interrupted at the deadline and its instance discarded
+func TestInvokeTimeout(t *testing.T) {
+
+	wasmBytes, err := wasmergo.Wat2Wasm(spinWat)
+	if err != nil {
+		t.Fatalf("compile wat error: %v", err)
+	}
+	_, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	vmPool, err := newVmPool(&contractId, wasmBytes,
+		&PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1, ExecuteTimeout: time.Millisecond * 100}, logger)
+	if err != nil {
+		t.Fatalf("create vmPool error: %v", err)
+	}
+	defer vmPool.close()
+
+	runtimeInst := RuntimeInstance{
+		pool: vmPool,
+		log:  logger,
+	}
+
+	ret := runtimeInst.Invoke(&contractId, "spin", wasmBytes, map[string][]byte{}, nil, 0)
+	if ret.Code != ContractCodeTimeout {
+		t.Fatalf("invoke spin should time out, code = %d, message = %s", ret.Code, ret.Message)
+	}
+	if ret.GasUsed != protocol.GasLimit {
+		t.Fatalf("invoke spin should use all its gas, used = %d", ret.GasUsed)
+	}
+
+	// the interrupted instance is replaced, so the pool still serves the next call
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ret = runtimeInst.InvokeWithContext(ctx, &contractId, "spin", wasmBytes, map[string][]byte{}, nil, 0)
+	if ret.Code != ContractCodeTimeout {
+		t.Fatalf("invoke spin should time out, code = %d, message = %s", ret.Code, ret.Message)
+	}
+}
+
+// TestWatchdogStopped a call stopped before ctx is done is not interrupted
+func TestWatchdogStopped(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	wd := startWatchdog(ctx, nil)
+	if wd.stop() {
+		t.Fatalf("watchdog should not fire before ctx is done")
+	}
+	cancel()
+	if wd.stop() {
+		t.Fatalf("watchdog should not fire once stopped")
+	}
+}
+
+// divideWat a contract whose `divide` method divides by zero
+const divideWat = `(module
+	(memory (export "memory") 1)
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "allocate") (param i32) (result i32) i32.const 0)
+	(func (export "deallocate") (param i32))
+	(func $divide (param i32) (result i32) i32.const 1 local.get 0 i32.div_s)
+	(func (export "divide") i32.const 0 call $divide drop))`
+
+// TestInvokeTrap the kind and the backtrace of a trap are in the contract result
+func TestInvokeTrap(t *testing.T) {
+
+	wasmBytes, err := wasmergo.Wat2Wasm(divideWat)
+	if err != nil {
+		t.Fatalf("compile wat error: %v", err)
+	}
+	_, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	vmPool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1}, logger)
+	if err != nil {
+		t.Fatalf("create vmPool error: %v", err)
+	}
+	defer vmPool.close()
+
+	runtimeInst := RuntimeInstance{
+		pool: vmPool,
+		log:  logger,
+	}
+
+	ret := runtimeInst.Invoke(&contractId, "divide", wasmBytes, map[string][]byte{}, nil, 0)
+	if ret.Code != ContractCodeTrap {
+		t.Fatalf("invoke divide should fail, code = %d, message = %s", ret.Code, ret.Message)
+	}
+	if !strings.Contains(ret.Message, "div-by-zero trap") || !strings.Contains(ret.Message, "divide at offset") {
+		t.Fatalf("unexpected message: %s", ret.Message)
+	}
+
+	// a method not exported is not a trap
+	ret = runtimeInst.Invoke(&contractId, "multiply", wasmBytes, map[string][]byte{}, nil, 0)
+	if ret.Code != ContractCodeMissingMethod {
+		t.Fatalf("invoke multiply should fail, code = %d, message = %s", ret.Code, ret.Message)
+	}
+}
+
+// loopWat a contract whose `loop` method never returns
+const loopWat = `(module
+	(memory (export "memory") 1)
+	(func (export "runtime_type") (result i32) i32.const 2)
+	(func (export "allocate") (param i32) (result i32) i32.const 0)
+	(func (export "deallocate") (param i32))
+	(func (export "loop") (loop $forever br $forever)))`
+
+// TestInvokeOutOfGas a contract out of gas is reported as such, with all its gas used
+func TestInvokeOutOfGas(t *testing.T) {
+
+	wasmBytes, err := wasmergo.Wat2Wasm(loopWat)
+	if err != nil {
+		t.Fatalf("compile wat error: %v", err)
+	}
+	_, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	vmPool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1}, logger)
+	if err != nil {
+		t.Fatalf("create vmPool error: %v", err)
+	}
+	defer vmPool.close()
+
+	runtimeInst := RuntimeInstance{
+		pool: vmPool,
+		log:  logger,
+	}
+
+	ret := runtimeInst.Invoke(&contractId, "loop", wasmBytes, map[string][]byte{}, nil, protocol.GasLimit-1000)
+	if ret.Code != ContractCodeOutOfGas {
+		t.Fatalf("invoke loop should run out of gas, code = %d, message = %s", ret.Code, ret.Message)
+	}
+	if ret.GasUsed != protocol.GasLimit {
+		t.Fatalf("invoke loop should use all its gas, used = %d", ret.GasUsed)
+	}
+}
@@ -4,11 +4,12 @@ import (
 	"chainmaker.org/chainmaker/common/v2/serialize"
 	"chainmaker.org/chainmaker/logger/v2"
 	"chainmaker.org/chainmaker/protocol/v2"
+	"context"
 	"fmt"
+	"github.com/jhyehuang/wasm-example/pkg/log"
 	"github.com/jhyehuang/wasm-example/pkg/wasmer-go"
 	"github.com/jhyehuang/wasm-example/src/wavm/common"
 	"strconv"
-	"sync"
 )
 
 // SimContext record the contract context
@@ -21,9 +22,18 @@ type SimContext struct {
 
 	method        string
 	parameters    map[string][]byte
+	ctx           context.Context // done when the invoke deadline expires, nil means no deadline
+	exports       *contractExports
 	CtxPtr        int32
 	GetStateCache []byte // cache call method GetStateLen value result, one cache per transaction
 
+	ContractEvents []*ContractEvent // events emitted by contract during this transaction
+}
+
+// ContractEvent an event emitted by contract through sys_call
+type ContractEvent struct {
+	Topic     string
+	EventData []string
 }
 
 // NewSimContext for every transaction
@@ -42,11 +52,12 @@ func NewSimContext(method string, log *logger.CMLogger, chainId string) *SimCont
 func (sc *SimContext) CallMethod(instance *wasmer.Instance) error {
 	var bytes []byte
 
-	runtimeFn, err := instance.Exports.GetRawFunction(protocol.ContractRuntimeTypeMethod)
-	if err != nil {
-		return fmt.Errorf("method [%s] not export, err = %v", protocol.ContractRuntimeTypeMethod, err)
+	if sc.exports == nil {
+		return fmt.Errorf("exports of the instance are not resolved")
+	}
+	if sc.CtxPtr == 0 {
+		return fmt.Errorf("context is not registered, %w", ErrCtxPtrExhausted)
 	}
-	defer runtimeFn.Close()
 
 	sc.parameters[protocol.ContractContextPtrParam] = []byte(strconv.Itoa(int(sc.CtxPtr)))
 	ec := serialize.NewEasyCodecWithMap(sc.parameters)
@@ -63,14 +74,12 @@ func (sc *SimContext) callContract(instance *wasmer.Instance, methodName string,
 
 	lengthOfSubject := len(bytes)
 
-	allocateFunc, err := instance.Exports.GetRawFunction(protocol.ContractAllocateMethod)
-	if err != nil {
-		return fmt.Errorf("method [%s] not export, err = %v", protocol.ContractAllocateMethod, err)
+	if sc.exports.allocate == nil {
+		return &missingMethodError{method: protocol.ContractAllocateMethod, legacy: true}
 	}
-	defer allocateFunc.Close()
 
 	// Allocate memory for the subject, and get a pointer to it.
-	allocateResult, err := allocateFunc.Call(lengthOfSubject)
+	allocateResult, err := sc.exports.allocate.Call(lengthOfSubject)
 	if err != nil {
 		sc.Log.Errorf("contract invoke %s failed, %s", protocol.ContractAllocateMethod, err.Error())
 		return fmt.Errorf("%s invoke failed. There may not be enough memory or CPU", protocol.ContractAllocateMethod)
@@ -81,27 +90,17 @@ func (sc *SimContext) callContract(instance *wasmer.Instance, methodName string,
 	}
 
 	// Write the subject into the memory.
-	exportMemory, err := instance.Exports.GetMemory("memory")
-	if err != nil {
-		return fmt.Errorf("[%s] can't get exported memory, err = %v", protocol.ContractAllocateMethod, err)
-	}
-	memory := exportMemory.Data()[dataPtr:]
-
-	//copy(memory, bytes)
-	for nth := 0; nth < lengthOfSubject; nth++ {
-		memory[nth] = bytes[nth]
+	if _, err = sc.exports.memory.WriteAt(bytes, int64(dataPtr)); err != nil {
+		return fmt.Errorf("[%s] write parameters failed, %s", protocol.ContractAllocateMethod, err.Error())
 	}
 
 	// Calls the `invoke` exported function. Given the pointer to the subject.
-	exportFunc, err := instance.Exports.GetRawFunction(methodName)
-	if err != nil {
+	exportFunc := sc.exports.method(methodName)
+	if exportFunc == nil {
 		// add compatibility for wasmer-1.0
-		if sc.TxSimContext.GetBlockVersion() < 2200 {
-			return fmt.Errorf("method [%s] not export", methodName)
-		}
-		return fmt.Errorf("find method [%s] failed, err = %v", methodName, err)
+		legacy := sc.TxSimContext != nil && sc.TxSimContext.GetBlockVersion() < 2200
+		return &missingMethodError{method: methodName, legacy: legacy}
 	}
-	defer exportFunc.Close()
 
 	_, err = exportFunc.Call()
 	if err != nil {
@@ -111,35 +110,58 @@ func (sc *SimContext) callContract(instance *wasmer.Instance, methodName string,
 	return err
 }
 
-// CallDeallocate deallocate vm memory before closing the instance
-func CallDeallocate(instance *wasmer.Instance) error {
-	instance.SetGasLimit(protocol.GasLimit)
-	deallocFunc, err := instance.Exports.GetFunction(protocol.ContractDeallocateMethod)
-	if err != nil {
+// missingMethodError the contract doesn't export the method called
+type missingMethodError struct {
+	method string
+	// legacy, the message of wasmer-1.0
+	legacy bool
+}
+
+func (e *missingMethodError) Error() string {
+	if e.legacy {
+		return fmt.Sprintf("method [%s] not export", e.method)
+	}
+	return fmt.Sprintf("find method [%s] failed, it is not exported", e.method)
+}
+
+// txId return the id of the transaction running, empty if unknown
+func (sc *SimContext) txId() string {
+	if sc.TxSimContext == nil || sc.TxSimContext.GetTx() == nil || sc.TxSimContext.GetTx().Payload == nil {
+		return ""
+	}
+	return sc.TxSimContext.GetTx().Payload.TxId
+}
+
+// callDeallocate deallocate vm memory before closing the instance, nothing to do
+// if the contract doesn't export deallocate
+func callDeallocate(instance *wasmer.Instance, exports *contractExports) error {
+	if exports.deallocate == nil {
+		return nil
+	}
+	if err := instance.SetGasLimit(protocol.GasLimit); err != nil {
 		return err
 	}
-	_, err = deallocFunc(0)
+	_, err := exports.deallocate.Call(0)
 	return err
 }
 
-// putCtxPointer revmoe SimContext from cache
+// removeCtxPointer remove SimContext from cache
 func (sc *SimContext) removeCtxPointer() {
-	//vbm := GetVmBridgeManager()
-	//vbm.remove(sc.CtxPtr)
+	if sc.CtxPtr == 0 {
+		return
+	}
+	vbm := GetVmBridgeManager()
+	vbm.remove(sc.CtxPtr)
+	sc.CtxPtr = 0
 }
 
-var ctxIndex = int32(0)
-var lock sync.Mutex
-
-// putCtxPointer save SimContext to cache
+// putCtxPointer save SimContext to cache, CtxPtr stays 0 if no pointer is left
 func (sc *SimContext) putCtxPointer() {
-	lock.Lock()
-	ctxIndex++
-	if ctxIndex > 1e8 {
-		ctxIndex = 0
+	vbm := GetVmBridgeManager()
+	ptr, err := vbm.register(sc)
+	if err != nil {
+		log.Errorf("register the context failed, %s", err.Error())
+		return
 	}
-	sc.CtxPtr = ctxIndex
-	lock.Unlock()
-	//vbm := GetVmBridgeManager()
-	//vbm.put(sc.CtxPtr, sc)
+	sc.CtxPtr = ptr
 }
@@ -0,0 +1,79 @@
+package txsim
+
+import (
+	"fmt"
+
+	storePb "chainmaker.org/chainmaker/pb-go/v2/store"
+)
+
+// kvIterator iterates over a snapshot of key/values taken when it is created,
+// implement protocol.StateIterator
+type kvIterator struct {
+	kvs   []*storePb.KV
+	index int
+}
+
+func newKvIterator(kvs []*storePb.KV) *kvIterator {
+	return &kvIterator{
+		kvs:   kvs,
+		index: -1,
+	}
+}
+
+// Next move to the next kv, return false when there is no more
+func (i *kvIterator) Next() bool {
+	if i.index+1 >= len(i.kvs) {
+		return false
+	}
+	i.index++
+	return true
+}
+
+// Value return the current kv
+func (i *kvIterator) Value() (*storePb.KV, error) {
+	if i.index < 0 || i.index >= len(i.kvs) {
+		return nil, fmt.Errorf("iterator has no current value")
+	}
+	return i.kvs[i.index], nil
+}
+
+// Release the iterator
+func (i *kvIterator) Release() {
+	i.kvs = nil
+}
+
+// keyHistoryIterator iterates over the modifications of one key,
+// implement protocol.KeyHistoryIterator
+type keyHistoryIterator struct {
+	modifications []*storePb.KeyModification
+	index         int
+}
+
+func newKeyHistoryIterator(modifications []*storePb.KeyModification) *keyHistoryIterator {
+	return &keyHistoryIterator{
+		modifications: modifications,
+		index:         -1,
+	}
+}
+
+// Next move to the next modification, return false when there is no more
+func (i *keyHistoryIterator) Next() bool {
+	if i.index+1 >= len(i.modifications) {
+		return false
+	}
+	i.index++
+	return true
+}
+
+// Value return the current modification
+func (i *keyHistoryIterator) Value() (*storePb.KeyModification, error) {
+	if i.index < 0 || i.index >= len(i.modifications) {
+		return nil, fmt.Errorf("iterator has no current value")
+	}
+	return i.modifications[i.index], nil
+}
+
+// Release the iterator
+func (i *keyHistoryIterator) Release() {
+	i.modifications = nil
+}
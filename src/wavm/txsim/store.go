@@ -0,0 +1,105 @@
+package txsim
+
+import (
+	"sort"
+	"sync"
+
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	storePb "chainmaker.org/chainmaker/pb-go/v2/store"
+)
+
+// stateKey identify a state by the contract it belongs to
+type stateKey struct {
+	contractName string
+	key          string
+}
+
+// Store is an in-memory world state shared by the TxSimContext of
+// consecutive transactions, the write set of a transaction becomes
+// visible to the next one after Commit
+type Store struct {
+	lock    sync.RWMutex
+	state   map[stateKey][]byte
+	history map[stateKey][]*storePb.KeyModification
+}
+
+// NewStore create an empty in-memory state
+func NewStore() *Store {
+	return &Store{
+		state:   make(map[stateKey][]byte),
+		history: make(map[stateKey][]*storePb.KeyModification),
+	}
+}
+
+// Get the committed value of key, nil if it does not exist
+func (s *Store) Get(contractName string, key []byte) []byte {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.state[stateKey{contractName, string(key)}]
+}
+
+// Put set the committed value of key directly, used to prepare state before running contracts
+func (s *Store) Put(contractName string, key []byte, value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.state[stateKey{contractName, string(key)}] = value
+}
+
+// Commit apply the write set of a transaction and record the key history
+func (s *Store) Commit(rwSet *commonPb.TxRWSet, blockHeight uint64, timestamp int64) {
+	if rwSet == nil {
+		return
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	for _, w := range rwSet.TxWrites {
+		k := stateKey{w.ContractName, string(w.Key)}
+		isDelete := w.Value == nil
+		if isDelete {
+			delete(s.state, k)
+		} else {
+			s.state[k] = w.Value
+		}
+		s.history[k] = append(s.history[k], &storePb.KeyModification{
+			TxId:        rwSet.TxId,
+			Value:       w.Value,
+			Timestamp:   timestamp,
+			IsDelete:    isDelete,
+			BlockHeight: blockHeight,
+		})
+	}
+}
+
+// keys return the sorted keys of contract in [startKey, limit)
+func (s *Store) keys(contractName string, startKey []byte, limit []byte) []string {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	var keys []string
+	for k := range s.state {
+		if k.contractName == contractName && inRange(k.key, startKey, limit) {
+			keys = append(keys, k.key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// modifications return a copy of the history of key, oldest first
+func (s *Store) modifications(contractName string, key []byte) []*storePb.KeyModification {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	history := s.history[stateKey{contractName, string(key)}]
+	result := make([]*storePb.KeyModification, len(history))
+	copy(result, history)
+	return result
+}
+
+func inRange(key string, startKey []byte, limit []byte) bool {
+	if key < string(startKey) {
+		return false
+	}
+	return len(limit) == 0 || key < string(limit)
+}
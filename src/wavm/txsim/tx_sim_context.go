@@ -0,0 +1,489 @@
+package txsim
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+
+	accessPb "chainmaker.org/chainmaker/pb-go/v2/accesscontrol"
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	configPb "chainmaker.org/chainmaker/pb-go/v2/config"
+	storePb "chainmaker.org/chainmaker/pb-go/v2/store"
+	vmPb "chainmaker.org/chainmaker/pb-go/v2/vm"
+	"chainmaker.org/chainmaker/protocol/v2"
+)
+
+// the length of the address calculated by GetStrAddrFromPbMember
+const addrLength = 40
+
+var _ protocol.TxSimContext = (*TxSimContext)(nil)
+
+// TxSimContext is an in-memory protocol.TxSimContext, it runs one transaction
+// against a Store and records the read/write set, so contracts can be run
+// end-to-end without a chain node
+type TxSimContext struct {
+	lock  sync.RWMutex
+	store *Store
+
+	tx             *commonPb.Transaction
+	txResult       *commonPb.Result
+	txExecSeq      int
+	blockHeight    uint64
+	blockVersion   uint32
+	blockTimestamp int64
+	blockProposer  *accessPb.Member
+	sender         *accessPb.Member
+	creators       map[string]*accessPb.Member
+	contracts      map[string]*commonPb.Contract
+	byteCodes      map[string][]byte
+	chainConfig    *configPb.ChainConfig
+	gasRemaining   uint64
+	currentResult  []byte
+
+	txReadKeyMap  map[stateKey]*commonPb.TxRead
+	txWriteKeyMap map[stateKey]*commonPb.TxWrite
+	iterHandles   map[int32]interface{}
+}
+
+// NewTxSimContext create a TxSimContext running tx against store,
+// the sender is taken from tx if it is signed
+func NewTxSimContext(store *Store, tx *commonPb.Transaction, blockHeight uint64,
+	blockVersion uint32) *TxSimContext {
+	sc := &TxSimContext{
+		store:         store,
+		tx:            tx,
+		blockHeight:   blockHeight,
+		blockVersion:  blockVersion,
+		creators:      make(map[string]*accessPb.Member),
+		contracts:     make(map[string]*commonPb.Contract),
+		byteCodes:     make(map[string][]byte),
+		gasRemaining:  uint64(protocol.GasLimit),
+		txReadKeyMap:  make(map[stateKey]*commonPb.TxRead),
+		txWriteKeyMap: make(map[stateKey]*commonPb.TxWrite),
+		iterHandles:   make(map[int32]interface{}),
+	}
+	if tx != nil && tx.Sender != nil {
+		sc.sender = tx.Sender.Signer
+	}
+	return sc
+}
+
+// SetSender set the invoker of the transaction
+func (s *TxSimContext) SetSender(sender *accessPb.Member) {
+	s.sender = sender
+}
+
+// SetCreator set the creator of contract
+func (s *TxSimContext) SetCreator(contractName string, creator *accessPb.Member) {
+	s.creators[contractName] = creator
+}
+
+// SetContract register a deployed contract and its byte code
+func (s *TxSimContext) SetContract(contract *commonPb.Contract, byteCode []byte) {
+	s.contracts[contract.Name] = contract
+	s.byteCodes[contract.Name] = byteCode
+}
+
+// SetBlockTimestamp set the timestamp of the current block
+func (s *TxSimContext) SetBlockTimestamp(timestamp int64) {
+	s.blockTimestamp = timestamp
+}
+
+// SetBlockProposer set the proposer of the current block
+func (s *TxSimContext) SetBlockProposer(proposer *accessPb.Member) {
+	s.blockProposer = proposer
+}
+
+// SetChainConfig set the config returned by GetLastChainConfig
+func (s *TxSimContext) SetChainConfig(chainConfig *configPb.ChainConfig) {
+	s.chainConfig = chainConfig
+}
+
+// SetGasLimit set the gas the transaction can use
+func (s *TxSimContext) SetGasLimit(gasLimit uint64) {
+	s.gasRemaining = gasLimit
+}
+
+// Commit apply the write set of this transaction to the store
+func (s *TxSimContext) Commit() {
+	s.store.Commit(s.GetTxRWSet(true), s.blockHeight, s.blockTimestamp)
+}
+
+// Get key from write set or store, record this operation to read set
+func (s *TxSimContext) Get(contractName string, key []byte) ([]byte, error) {
+	value, err := s.GetNoRecord(contractName, key)
+	if err != nil {
+		return nil, err
+	}
+	s.PutIntoReadSet(contractName, key, value)
+	return value, nil
+}
+
+// GetWithRecord get key from write set or store, record this operation to read set
+func (s *TxSimContext) GetWithRecord(contractName string, key []byte) ([]byte, error) {
+	return s.Get(contractName, key)
+}
+
+// GetNoRecord read data from write set or store, but not record into read set
+func (s *TxSimContext) GetNoRecord(contractName string, key []byte) ([]byte, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	k := stateKey{contractName, string(key)}
+	if w, ok := s.txWriteKeyMap[k]; ok {
+		return w.Value, nil
+	}
+	if r, ok := s.txReadKeyMap[k]; ok {
+		return r.Value, nil
+	}
+	return s.store.Get(contractName, key), nil
+}
+
+// GetSnapshot snapshot is not supported in memory
+func (s *TxSimContext) GetSnapshot() protocol.Snapshot {
+	return nil
+}
+
+// Put key into write set
+func (s *TxSimContext) Put(name string, key []byte, value []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.txWriteKeyMap[stateKey{name, string(key)}] = &commonPb.TxWrite{
+		Key:          key,
+		Value:        value,
+		ContractName: name,
+	}
+	return nil
+}
+
+// PutRecord sql state is not supported in memory, do nothing
+func (s *TxSimContext) PutRecord(contractName string, value []byte, sqlType protocol.SqlType) {
+}
+
+// PutIntoReadSet put kv to read set
+func (s *TxSimContext) PutIntoReadSet(contractName string, key []byte, value []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.txReadKeyMap[stateKey{contractName, string(key)}] = &commonPb.TxRead{
+		Key:          key,
+		Value:        value,
+		ContractName: contractName,
+	}
+}
+
+// Del delete key, a nil value is written into write set
+func (s *TxSimContext) Del(name string, key []byte) error {
+	return s.Put(name, key, nil)
+}
+
+// Select range query for key [startKey, limit), the write set of this transaction
+// is merged over the store
+func (s *TxSimContext) Select(name string, startKey []byte, limit []byte) (protocol.StateIterator, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	values := make(map[string][]byte)
+	for _, key := range s.store.keys(name, startKey, limit) {
+		values[key] = s.store.Get(name, []byte(key))
+	}
+	for k, w := range s.txWriteKeyMap {
+		if k.contractName == name && inRange(k.key, startKey, limit) {
+			values[k.key] = w.Value
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		if value != nil {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	kvs := make([]*storePb.KV, 0, len(keys))
+	for _, key := range keys {
+		kvs = append(kvs, &storePb.KV{
+			ContractName: name,
+			Key:          []byte(key),
+			Value:        values[key],
+		})
+	}
+	return newKvIterator(kvs), nil
+}
+
+// GetHistoryIterForKey query the committed change history of a key
+func (s *TxSimContext) GetHistoryIterForKey(contractName string, key []byte) (protocol.KeyHistoryIterator, error) {
+	return newKeyHistoryIterator(s.store.modifications(contractName, key)), nil
+}
+
+// CallContract cross contract call is not supported in memory
+func (s *TxSimContext) CallContract(caller, contract *commonPb.Contract, method string, byteCode []byte,
+	parameter map[string][]byte, gasUsed uint64, refTxType commonPb.TxType) (
+	*commonPb.ContractResult, protocol.ExecOrderTxType, commonPb.TxStatusCode) {
+	result := &commonPb.ContractResult{
+		Code:    1,
+		Message: fmt.Sprintf("cross contract call [%s] is not supported", contract.Name),
+		GasUsed: gasUsed,
+	}
+	s.currentResult = nil
+	return result, protocol.ExecOrderTxTypeNormal, commonPb.TxStatusCode_CONTRACT_FAIL
+}
+
+// GetCurrentResult get cross contract call result
+func (s *TxSimContext) GetCurrentResult() []byte {
+	return s.currentResult
+}
+
+// GetTx get related transaction
+func (s *TxSimContext) GetTx() *commonPb.Transaction {
+	return s.tx
+}
+
+// GetBlockHeight returns current block height
+func (s *TxSimContext) GetBlockHeight() uint64 {
+	return s.blockHeight
+}
+
+// GetBlockFingerprint returns unique id for block
+func (s *TxSimContext) GetBlockFingerprint() string {
+	return fmt.Sprintf("%d", s.blockHeight)
+}
+
+// GetBlockTimestamp returns current block timestamp
+func (s *TxSimContext) GetBlockTimestamp() int64 {
+	return s.blockTimestamp
+}
+
+// GetBlockProposer returns current block proposer
+func (s *TxSimContext) GetBlockProposer() *accessPb.Member {
+	return s.blockProposer
+}
+
+// GetTxResult returns the tx result
+func (s *TxSimContext) GetTxResult() *commonPb.Result {
+	return s.txResult
+}
+
+// SetTxResult set tx result
+func (s *TxSimContext) SetTxResult(txResult *commonPb.Result) {
+	s.txResult = txResult
+}
+
+// GetTxRWSet returns the read and write set of the transaction sorted by key,
+// the write set is dropped if vm failed
+func (s *TxSimContext) GetTxRWSet(runVmSuccess bool) *commonPb.TxRWSet {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	rwSet := &commonPb.TxRWSet{
+		TxId:     s.txId(),
+		TxReads:  make([]*commonPb.TxRead, 0, len(s.txReadKeyMap)),
+		TxWrites: make([]*commonPb.TxWrite, 0, len(s.txWriteKeyMap)),
+	}
+	readKeys := make([]stateKey, 0, len(s.txReadKeyMap))
+	for k := range s.txReadKeyMap {
+		readKeys = append(readKeys, k)
+	}
+	for _, k := range sortStateKeys(readKeys) {
+		rwSet.TxReads = append(rwSet.TxReads, s.txReadKeyMap[k])
+	}
+	if !runVmSuccess {
+		return rwSet
+	}
+
+	writeKeys := make([]stateKey, 0, len(s.txWriteKeyMap))
+	for k := range s.txWriteKeyMap {
+		writeKeys = append(writeKeys, k)
+	}
+	for _, k := range sortStateKeys(writeKeys) {
+		rwSet.TxWrites = append(rwSet.TxWrites, s.txWriteKeyMap[k])
+	}
+	return rwSet
+}
+
+// GetCreator returns the creator of the contract
+func (s *TxSimContext) GetCreator(namespace string) *accessPb.Member {
+	return s.creators[namespace]
+}
+
+// GetSender returns the invoker of the transaction
+func (s *TxSimContext) GetSender() *accessPb.Member {
+	return s.sender
+}
+
+// GetBlockchainStore there is no blockchain store in memory
+func (s *TxSimContext) GetBlockchainStore() protocol.BlockchainStore {
+	return nil
+}
+
+// GetLastChainConfig returns the config set by SetChainConfig
+func (s *TxSimContext) GetLastChainConfig() *configPb.ChainConfig {
+	return s.chainConfig
+}
+
+// GetAccessControl access control is not supported in memory
+func (s *TxSimContext) GetAccessControl() (protocol.AccessControlProvider, error) {
+	return nil, fmt.Errorf("access control is not supported in memory")
+}
+
+// GetChainNodesInfoProvider chain nodes info is not supported in memory
+func (s *TxSimContext) GetChainNodesInfoProvider() (protocol.ChainNodesInfoProvider, error) {
+	return nil, fmt.Errorf("chain nodes info is not supported in memory")
+}
+
+// GetTxExecSeq returns the execution sequence of the transaction
+func (s *TxSimContext) GetTxExecSeq() int {
+	return s.txExecSeq
+}
+
+// SetTxExecSeq set the execution sequence of the transaction
+func (s *TxSimContext) SetTxExecSeq(txExecSeq int) {
+	s.txExecSeq = txExecSeq
+}
+
+// GetDepth cross contract call is not supported, so depth is always 0
+func (s *TxSimContext) GetDepth() int {
+	return 0
+}
+
+// SetIterHandle save the iterator opened by contract
+func (s *TxSimContext) SetIterHandle(index int32, iter interface{}) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.iterHandles[index] = iter
+}
+
+// GetIterHandle get the iterator opened by contract
+func (s *TxSimContext) GetIterHandle(index int32) (interface{}, bool) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	iter, ok := s.iterHandles[index]
+	return iter, ok
+}
+
+// GetBlockVersion returns the block version
+func (s *TxSimContext) GetBlockVersion() uint32 {
+	return s.blockVersion
+}
+
+// GetContractByName get contract info registered by SetContract
+func (s *TxSimContext) GetContractByName(name string) (*commonPb.Contract, error) {
+	contract, ok := s.contracts[name]
+	if !ok {
+		return nil, fmt.Errorf("contract [%s] not found", name)
+	}
+	return contract, nil
+}
+
+// GetContractBytecode get contract byte code registered by SetContract
+func (s *TxSimContext) GetContractBytecode(name string) ([]byte, error) {
+	byteCode, ok := s.byteCodes[name]
+	if !ok {
+		return nil, fmt.Errorf("contract [%s] byte code not found", name)
+	}
+	return byteCode, nil
+}
+
+// GetTxRWMapByContractName get the read-write map of the specified contract of the current transaction
+func (s *TxSimContext) GetTxRWMapByContractName(contractName string) (
+	map[string]*commonPb.TxRead, map[string]*commonPb.TxWrite) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	reads := make(map[string]*commonPb.TxRead)
+	for k, r := range s.txReadKeyMap {
+		if k.contractName == contractName {
+			reads[k.key] = r
+		}
+	}
+	writes := make(map[string]*commonPb.TxWrite)
+	for k, w := range s.txWriteKeyMap {
+		if k.contractName == contractName {
+			writes[k.key] = w
+		}
+	}
+	return reads, writes
+}
+
+// GetCrossInfo cross contract call is not supported, so there is no call link
+func (s *TxSimContext) GetCrossInfo() uint64 {
+	return 0
+}
+
+// HasUsed cross contract call is not supported, so no runtime type is used before
+func (s *TxSimContext) HasUsed(runtimeType commonPb.RuntimeType) bool {
+	return false
+}
+
+// RecordRuntimeTypeIntoCrossInfo cross contract call is not supported, do nothing
+func (s *TxSimContext) RecordRuntimeTypeIntoCrossInfo(runtimeType commonPb.RuntimeType) {
+}
+
+// RemoveRuntimeTypeFromCrossInfo cross contract call is not supported, do nothing
+func (s *TxSimContext) RemoveRuntimeTypeFromCrossInfo() {
+}
+
+// GetStrAddrFromPbMember calculate a hex address from the hash of member info
+func (s *TxSimContext) GetStrAddrFromPbMember(pbMember *accessPb.Member) (string, error) {
+	if pbMember == nil {
+		return "", fmt.Errorf("member is nil")
+	}
+	hash := sha256.Sum256(pbMember.MemberInfo)
+	return hex.EncodeToString(hash[:])[:addrLength], nil
+}
+
+// SubtractGas subtract gas from the gas remaining
+func (s *TxSimContext) SubtractGas(gasUsed uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if gasUsed > s.gasRemaining {
+		return fmt.Errorf("gas not enough, remaining %d, need %d", s.gasRemaining, gasUsed)
+	}
+	s.gasRemaining -= gasUsed
+	return nil
+}
+
+// GetGasRemaining returns gas remaining
+func (s *TxSimContext) GetGasRemaining() uint64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.gasRemaining
+}
+
+// GetKeys get the values of a batch of keys, record them to read set
+func (s *TxSimContext) GetKeys(keys []*vmPb.BatchKey) ([]*vmPb.BatchKey, error) {
+	for _, key := range keys {
+		value, err := s.Get(key.ContractName, batchKey(key.Key, key.Field))
+		if err != nil {
+			return nil, err
+		}
+		key.Value = value
+	}
+	return keys, nil
+}
+
+func (s *TxSimContext) txId() string {
+	if s.tx == nil || s.tx.Payload == nil {
+		return ""
+	}
+	return s.tx.Payload.TxId
+}
+
+func batchKey(key string, field string) []byte {
+	if field == "" {
+		return []byte(key)
+	}
+	return []byte(key + protocol.ContractStoreSeparator + field)
+}
+
+func sortStateKeys(keys []stateKey) []stateKey {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].contractName != keys[j].contractName {
+			return keys[i].contractName < keys[j].contractName
+		}
+		return keys[i].key < keys[j].key
+	})
+	return keys
+}
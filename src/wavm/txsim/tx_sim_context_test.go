@@ -0,0 +1,115 @@
+package txsim
+
+import (
+	"testing"
+
+	commonPb "chainmaker.org/chainmaker/pb-go/v2/common"
+	"github.com/stretchr/testify/assert"
+)
+
+const contractName = "counter"
+
+func newTestTxSimContext(store *Store, txId string) *TxSimContext {
+	tx := &commonPb.Transaction{
+		Payload: &commonPb.Payload{
+			TxId:         txId,
+			ContractName: contractName,
+		},
+	}
+	return NewTxSimContext(store, tx, 1, 2300)
+}
+
+func TestTxSimContextReadWriteSet(t *testing.T) {
+	store := NewStore()
+	store.Put(contractName, []byte("a"), []byte("1"))
+
+	sc := newTestTxSimContext(store, "tx1")
+	value, err := sc.Get(contractName, []byte("a"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("1"), value)
+
+	assert.NoError(t, sc.Put(contractName, []byte("b"), []byte("2")))
+	value, err = sc.Get(contractName, []byte("b"))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("2"), value)
+
+	rwSet := sc.GetTxRWSet(true)
+	assert.Equal(t, "tx1", rwSet.TxId)
+	assert.Len(t, rwSet.TxReads, 2)
+	assert.Len(t, rwSet.TxWrites, 1)
+	assert.Empty(t, sc.GetTxRWSet(false).TxWrites)
+
+	// nothing is visible to the store before commit
+	assert.Nil(t, store.Get(contractName, []byte("b")))
+	sc.Commit()
+	assert.Equal(t, []byte("2"), store.Get(contractName, []byte("b")))
+}
+
+func TestTxSimContextDel(t *testing.T) {
+	store := NewStore()
+	store.Put(contractName, []byte("a"), []byte("1"))
+
+	sc := newTestTxSimContext(store, "tx1")
+	assert.NoError(t, sc.Del(contractName, []byte("a")))
+	value, err := sc.Get(contractName, []byte("a"))
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	sc.Commit()
+	assert.Nil(t, store.Get(contractName, []byte("a")))
+}
+
+func TestTxSimContextSelect(t *testing.T) {
+	store := NewStore()
+	store.Put(contractName, []byte("k1"), []byte("1"))
+	store.Put(contractName, []byte("k2"), []byte("2"))
+	store.Put(contractName, []byte("k4"), []byte("4"))
+	store.Put("other", []byte("k3"), []byte("x"))
+
+	sc := newTestTxSimContext(store, "tx1")
+	assert.NoError(t, sc.Put(contractName, []byte("k3"), []byte("3")))
+	assert.NoError(t, sc.Del(contractName, []byte("k2")))
+
+	iter, err := sc.Select(contractName, []byte("k1"), []byte("k4"))
+	assert.NoError(t, err)
+	defer iter.Release()
+
+	var keys []string
+	for iter.Next() {
+		kv, err := iter.Value()
+		assert.NoError(t, err)
+		keys = append(keys, string(kv.Key))
+	}
+	assert.Equal(t, []string{"k1", "k3"}, keys)
+}
+
+func TestTxSimContextHistory(t *testing.T) {
+	store := NewStore()
+
+	for _, txId := range []string{"tx1", "tx2"} {
+		sc := newTestTxSimContext(store, txId)
+		assert.NoError(t, sc.Put(contractName, []byte("a"), []byte(txId)))
+		sc.Commit()
+	}
+
+	sc := newTestTxSimContext(store, "tx3")
+	iter, err := sc.GetHistoryIterForKey(contractName, []byte("a"))
+	assert.NoError(t, err)
+
+	var txIds []string
+	for iter.Next() {
+		km, err := iter.Value()
+		assert.NoError(t, err)
+		txIds = append(txIds, km.TxId)
+	}
+	assert.Equal(t, []string{"tx1", "tx2"}, txIds)
+}
+
+func TestTxSimContextGas(t *testing.T) {
+	sc := newTestTxSimContext(NewStore(), "tx1")
+	sc.SetGasLimit(100)
+
+	assert.NoError(t, sc.SubtractGas(60))
+	assert.Equal(t, uint64(40), sc.GetGasRemaining())
+	assert.Error(t, sc.SubtractGas(60))
+}
@@ -0,0 +1,139 @@
+package wavm
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jhyehuang/wasm-example/pkg/log"
+)
+
+const (
+	// the biggest context pointer handed out, then it wraps around to minCtxPtr
+	maxCtxPtr = int32(math.MaxInt32)
+	// 0 is never used, so a zero CtxPtr always means "not registered"
+	minCtxPtr = int32(1)
+	// a context still registered after this long is reported as leaked
+	defaultLeakThreshold = time.Minute * 10
+	// how often the leak detector scans the registry
+	defaultLeakCheckInterval = time.Minute
+)
+
+// ErrCtxPtrExhausted every context pointer is taken by a live context
+var ErrCtxPtrExhausted = errors.New("no context pointer left, every one is in use")
+
+var vbm *vmBridgeManager
+var vbmOnce sync.Once
+
+// bridgeEntry a registered context and the time it was registered
+type bridgeEntry struct {
+	sc           *SimContext
+	registerTime time.Time
+}
+
+// vmBridgeManager maps the context pointer handed to a contract back to
+// the SimContext of the transaction being executed
+type vmBridgeManager struct {
+	lock     sync.RWMutex
+	ctxIndex int32
+	// the biggest pointer handed out, maxCtxPtr but in tests
+	maxPtr       int32
+	contextCache map[int32]*bridgeEntry
+}
+
+// GetVmBridgeManager get singleton vmBridgeManager struct
+func GetVmBridgeManager() *vmBridgeManager {
+	vbmOnce.Do(func() {
+		vbm = newVmBridgeManager()
+		go vbm.startLeakDetectLoop(defaultLeakCheckInterval, defaultLeakThreshold)
+	})
+	return vbm
+}
+
+func newVmBridgeManager() *vmBridgeManager {
+	return &vmBridgeManager{
+		ctxIndex:     0,
+		maxPtr:       maxCtxPtr,
+		contextCache: make(map[int32]*bridgeEntry),
+	}
+}
+
+// register the context and assign it a pointer no live context is using,
+// the pointer wraps around after maxCtxPtr and skips ones still in use,
+// ErrCtxPtrExhausted is returned once every pointer is probed
+func (b *vmBridgeManager) register(sc *SimContext) (int32, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for probes := int64(b.maxPtr-minCtxPtr) + 1; probes > 0; probes-- {
+		if b.ctxIndex >= b.maxPtr {
+			b.ctxIndex = minCtxPtr
+		} else {
+			b.ctxIndex++
+		}
+		if _, exists := b.contextCache[b.ctxIndex]; exists {
+			continue
+		}
+		b.contextCache[b.ctxIndex] = &bridgeEntry{
+			sc:           sc,
+			registerTime: time.Now(),
+		}
+		return b.ctxIndex, nil
+	}
+	return 0, ErrCtxPtrExhausted
+}
+
+// get the context
+func (b *vmBridgeManager) get(k int32) *SimContext {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	entry, exists := b.contextCache[k]
+	if !exists {
+		return nil
+	}
+	return entry.sc
+}
+
+// remove the context
+func (b *vmBridgeManager) remove(k int32) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	delete(b.contextCache, k)
+}
+
+// size return the count of live contexts
+func (b *vmBridgeManager) size() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return len(b.contextCache)
+}
+
+// leaked return the pointers registered longer than threshold ago,
+// they belong to transactions that never called removeCtxPointer
+func (b *vmBridgeManager) leaked(threshold time.Duration) []int32 {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+
+	var ptrs []int32
+	now := time.Now()
+	for k, entry := range b.contextCache {
+		if now.Sub(entry.registerTime) > threshold {
+			ptrs = append(ptrs, k)
+		}
+	}
+	return ptrs
+}
+
+// startLeakDetectLoop report leaked contexts periodically
+func (b *vmBridgeManager) startLeakDetectLoop(interval time.Duration, threshold time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ptrs := b.leaked(threshold)
+		if len(ptrs) > 0 {
+			log.Warnf("vm bridge found %d context(s) registered more than %v, ctx_ptr = %v",
+				len(ptrs), threshold, ptrs)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package wavm
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVmBridgeManagerRegister(t *testing.T) {
+	b := newVmBridgeManager()
+
+	sc := &SimContext{}
+	ptr, err := b.register(sc)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), ptr)
+	assert.Equal(t, sc, b.get(ptr))
+
+	b.remove(ptr)
+	assert.Nil(t, b.get(ptr))
+	assert.Equal(t, 0, b.size())
+}
+
+func TestVmBridgeManagerWrapAround(t *testing.T) {
+	b := newVmBridgeManager()
+
+	live := &SimContext{}
+	livePtr, _ := b.register(live)
+	assert.Equal(t, minCtxPtr, livePtr)
+
+	b.ctxIndex = maxCtxPtr - 1
+	ptr, _ := b.register(&SimContext{})
+	assert.Equal(t, maxCtxPtr, ptr)
+
+	// minCtxPtr is still in use, so it must be skipped after wrapping
+	ptr, _ = b.register(&SimContext{})
+	assert.Equal(t, minCtxPtr+1, ptr)
+	assert.Equal(t, live, b.get(livePtr))
+}
+
+func TestVmBridgeManagerExhausted(t *testing.T) {
+	b := newVmBridgeManager()
+	b.maxPtr = 3
+
+	for ptr := minCtxPtr; ptr <= b.maxPtr; ptr++ {
+		_, err := b.register(&SimContext{})
+		assert.NoError(t, err)
+	}
+	_, err := b.register(&SimContext{})
+	assert.ErrorIs(t, err, ErrCtxPtrExhausted)
+
+	// a pointer removed is handed out again
+	b.remove(2)
+	ptr, err := b.register(&SimContext{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), ptr)
+}
+
+func TestVmBridgeManagerConcurrent(t *testing.T) {
+	b := newVmBridgeManager()
+
+	var wg sync.WaitGroup
+	var lock sync.Mutex
+	seen := make(map[int32]bool)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sc := &SimContext{}
+			ptr, err := b.register(sc)
+			assert.NoError(t, err)
+			lock.Lock()
+			assert.False(t, seen[ptr])
+			seen[ptr] = true
+			lock.Unlock()
+			assert.Equal(t, sc, b.get(ptr))
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 100, b.size())
+}
+
+func TestVmBridgeManagerLeaked(t *testing.T) {
+	b := newVmBridgeManager()
+
+	ptr, _ := b.register(&SimContext{})
+	assert.Empty(t, b.leaked(time.Hour))
+
+	b.contextCache[ptr].registerTime = time.Now().Add(-time.Hour * 2)
+	assert.Equal(t, []int32{ptr}, b.leaked(time.Hour))
+}
+
+func TestSimContextRemoveCtxPointer(t *testing.T) {
+	sc := NewSimContext("increase", nil, "")
+	ptr := sc.CtxPtr
+	assert.Equal(t, sc, GetVmBridgeManager().get(ptr))
+
+	sc.removeCtxPointer()
+	assert.Nil(t, GetVmBridgeManager().get(ptr))
+	assert.Equal(t, int32(0), sc.CtxPtr)
+}
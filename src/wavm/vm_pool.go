@@ -3,6 +3,8 @@ package wavm
 import (
 	"chainmaker.org/chainmaker/common/v2/random/uuid"
 	"chainmaker.org/chainmaker/logger/v2"
+	"context"
+	"errors"
 	"fmt"
 	"github.com/jhyehuang/wasm-example/pkg/log"
 	"github.com/jhyehuang/wasm-example/pkg/utils"
@@ -27,24 +29,182 @@ const (
 	defaultApplyThreshold = 100
 	// if wasmer instance invoke error more than N times, should close and discard this instance
 	defaultDiscardCount = 10
+	// how long Invoke waits for an instance before giving up
+	defaultGetInstanceTimeout = time.Second * 10
+	// how long a contract may run in Invoke before it is interrupted
+	defaultExecuteTimeout = time.Second * 30
 )
 
-// GetInstance get a vm instance to run contract
-// should be followed by defer resetInstance
-func (p *vmPool) GetInstance() *wrappedInstance {
+// ErrPoolClosed the pool was closed before an instance could be handed out
+var ErrPoolClosed = errors.New("vm pool closed")
+
+// GetInstanceError returned by GetInstance when no instance can be handed out,
+// Err is ErrPoolClosed, context.DeadlineExceeded or context.Canceled
+type GetInstanceError struct {
+	// contract name and version, e.g. counter_1.0.0
+	Contract string
+	Err      error
+}
+
+func (e *GetInstanceError) Error() string {
+	return fmt.Sprintf("[%s] get instance from vm pool failed, %s", e.Contract, e.Err.Error())
+}
+
+// Unwrap return the cause, so errors.Is(err, ErrPoolClosed) works
+func (e *GetInstanceError) Unwrap() error {
+	return e.Err
+}
+
+// PoolOptions controls the size of a vmPool and when it grows, shrinks
+// or discards instances, zero or nil fields take the default value, the
+// pointer fields are those a zero is meaningful for, see Int32
+type PoolOptions struct {
+	// the max pool size, also the capacity of the instance channel
+	MaxSize int32
+	// the min pool size, 0 starts the pool empty, it grows on the first GetInstance
+	MinSize *int32
+	// how many instances the pool grows or shrinks by once
+	ChangeSize int32
+	// refresh vmPool time, use for grow or shrink
+	RefreshTime time.Duration
+	// if get instance avg time greater than this value, should grow pool, Millisecond as unit
+	DelayTolerance *int32
+	// if apply times greater than this value, should grow pool
+	ApplyThreshold *int32
+	// if wasmer instance invoke error more than N times, should close and discard this instance,
+	// 0 discards it at its first error
+	DiscardCount *int32
+	// how long Invoke waits for an instance before giving up
+	GetInstanceTimeout time.Duration
+	// how long a contract may run in Invoke before it is interrupted
+	ExecuteTimeout time.Duration
+	// compiled modules are loaded from and stored to it, nil compiles every time
+	ModuleCache *ModuleCache
+	// contracts the linter reports issues for are rejected, nil skips the linter
+	LintOptions *LintOptions
+	// the exports a contract must have, checked when the pool is created, nil is DefaultContractABI
+	ABI *ContractABI
+}
+
+// DefaultPoolOptions return the options used when newVmPool is given nil
+func DefaultPoolOptions() *PoolOptions {
+	return &PoolOptions{
+		MaxSize:            defaultMaxSize,
+		MinSize:            Int32(defaultMinSize),
+		ChangeSize:         defaultChangeSize,
+		RefreshTime:        defaultRefreshTime,
+		DelayTolerance:     Int32(defaultDelayTolerance),
+		ApplyThreshold:     Int32(defaultApplyThreshold),
+		DiscardCount:       Int32(defaultDiscardCount),
+		GetInstanceTimeout: defaultGetInstanceTimeout,
+		ExecuteTimeout:     defaultExecuteTimeout,
+		ABI:                DefaultContractABI(),
+	}
+}
+
+// Int32 return a pointer to v, to set the pointer fields of PoolOptions, e.g. Int32(0)
+func Int32(v int32) *int32 {
+	return &v
+}
+
+// withDefaults return a copy of the options with zero and nil fields set to default
+func (o *PoolOptions) withDefaults() *PoolOptions {
+	options := DefaultPoolOptions()
+	if o == nil {
+		return options
+	}
+	if o.MaxSize != 0 {
+		options.MaxSize = o.MaxSize
+	}
+	if o.MinSize != nil {
+		options.MinSize = Int32(*o.MinSize)
+	}
+	if o.ChangeSize != 0 {
+		options.ChangeSize = o.ChangeSize
+	}
+	if o.RefreshTime != 0 {
+		options.RefreshTime = o.RefreshTime
+	}
+	if o.DelayTolerance != nil {
+		options.DelayTolerance = Int32(*o.DelayTolerance)
+	}
+	if o.ApplyThreshold != nil {
+		options.ApplyThreshold = Int32(*o.ApplyThreshold)
+	}
+	if o.DiscardCount != nil {
+		options.DiscardCount = Int32(*o.DiscardCount)
+	}
+	if o.GetInstanceTimeout != 0 {
+		options.GetInstanceTimeout = o.GetInstanceTimeout
+	}
+	if o.ExecuteTimeout != 0 {
+		options.ExecuteTimeout = o.ExecuteTimeout
+	}
+	options.ModuleCache = o.ModuleCache
+	options.LintOptions = o.LintOptions
+	if o.ABI != nil {
+		options.ABI = o.ABI
+	}
+	return options
+}
+
+// Validate check the options, once the defaults are applied, are consistent with each other
+func (o *PoolOptions) Validate() error {
+	o = o.withDefaults()
+	if *o.MinSize < 0 {
+		return fmt.Errorf("pool min size %d should not be negative", *o.MinSize)
+	}
+	if o.MaxSize < *o.MinSize || o.MaxSize <= 0 {
+		return fmt.Errorf("pool max size %d should be positive and not less than min size %d", o.MaxSize,
+			*o.MinSize)
+	}
+	if o.ChangeSize <= 0 || o.ChangeSize > o.MaxSize {
+		return fmt.Errorf("pool change size %d should be in (0, %d]", o.ChangeSize, o.MaxSize)
+	}
+	if o.RefreshTime <= 0 {
+		return fmt.Errorf("pool refresh time %v should be positive", o.RefreshTime)
+	}
+	if *o.DelayTolerance < 0 {
+		return fmt.Errorf("pool delay tolerance %d should not be negative", *o.DelayTolerance)
+	}
+	if *o.ApplyThreshold < 0 {
+		return fmt.Errorf("pool apply threshold %d should not be negative", *o.ApplyThreshold)
+	}
+	if *o.DiscardCount < 0 {
+		return fmt.Errorf("pool discard count %d should not be negative", *o.DiscardCount)
+	}
+	if o.GetInstanceTimeout <= 0 {
+		return fmt.Errorf("pool get instance timeout %v should be positive", o.GetInstanceTimeout)
+	}
+	if o.ExecuteTimeout <= 0 {
+		return fmt.Errorf("pool execute timeout %v should be positive", o.ExecuteTimeout)
+	}
+	return nil
+}
+
+// GetInstance get a vm instance to run contract, wait until one is available,
+// ctx is done or the pool is closed, should be followed by defer RevertInstance
+func (p *vmPool) GetInstance(ctx context.Context) (*wrappedInstance, error) {
+
+	// a closed pool hands out nothing, even if instances are still being drained
+	select {
+	case <-p.closeC:
+		return nil, p.newGetInstanceError(ErrPoolClosed)
+	default:
+	}
 
-	var instance *wrappedInstance
 	// get instance from vm pool
 	select {
-	case instance = <-p.instances:
+	case instance, ok := <-p.instances:
+		if !ok {
+			return nil, p.newGetInstanceError(ErrPoolClosed)
+		}
 		// concurrency safe here
 		atomic.AddInt32(&p.useCount, 1)
 		instance.lastUseTime = utils.CurrentTimeMillisSeconds()
-		return instance
+		poolWaitHistogram.Observe(0, contractKey(p.contractId))
+		return instance, nil
 	default:
-		// nothing
-	}
-	if instance == nil {
 		log.Debugf("can't get wrappedInstance from vmPool.")
 	}
 
@@ -52,28 +212,54 @@ func (p *vmPool) GetInstance() *wrappedInstance {
 	// add wait time to total delay
 	curTimeMS1 := utils.CurrentTimeMillisSeconds()
 	go func() {
-		p.applySignalC <- struct{}{}
-		log.Debugf("send 'applySignal' to vmPool.")
+		// the refreshing loop no longer receives once the pool is closed
+		select {
+		case p.applySignalC <- struct{}{}:
+			log.Debugf("send 'applySignal' to vmPool.")
+		case <-p.closeC:
+		}
 	}()
 
-	instance = <-p.instances
-	log.Debugf("got an wrappedInstance from vmPool.")
-	atomic.AddInt32(&p.useCount, 1)
-	curTimeMS2 := utils.CurrentTimeMillisSeconds()
-	instance.lastUseTime = curTimeMS2
-	elapsedTimeMS := int32(curTimeMS2 - curTimeMS1)
-	atomic.AddInt32(&p.totalDelay, elapsedTimeMS)
+	select {
+	case instance, ok := <-p.instances:
+		if !ok {
+			return nil, p.newGetInstanceError(ErrPoolClosed)
+		}
+		log.Debugf("got an wrappedInstance from vmPool.")
+		atomic.AddInt32(&p.useCount, 1)
+		curTimeMS2 := utils.CurrentTimeMillisSeconds()
+		instance.lastUseTime = curTimeMS2
+		elapsedTimeMS := int32(curTimeMS2 - curTimeMS1)
+		atomic.AddInt32(&p.totalDelay, elapsedTimeMS)
+		poolWaitHistogram.Observe(float64(elapsedTimeMS)/1000, contractKey(p.contractId))
+		return instance, nil
+	case <-p.closeC:
+		return nil, p.newGetInstanceError(ErrPoolClosed)
+	case <-ctx.Done():
+		atomic.AddInt32(&p.totalDelay, int32(utils.CurrentTimeMillisSeconds()-curTimeMS1))
+		return nil, p.newGetInstanceError(ctx.Err())
+	}
+}
 
-	return instance
+func (p *vmPool) newGetInstanceError(err error) *GetInstanceError {
+	return &GetInstanceError{
+		Contract: contractKey(p.contractId),
+		Err:      err,
+	}
 }
 
 // RevertInstance revert instance to pool
 func (p *vmPool) RevertInstance(instance *wrappedInstance) {
 	if p.shouldDiscard(instance) {
+		poolDiscardCounter.Inc(contractKey(p.contractId))
 		go func() {
-			p.removeInstanceC <- struct{}{}
-			p.addInstanceC <- struct{}{}
 			p.CloseInstance(instance)
+			// always received, the refreshing loop counts it even while draining on close
+			p.removeInstanceC <- struct{}{}
+			select {
+			case p.addInstanceC <- struct{}{}:
+			case <-p.closeC:
+			}
 		}()
 	} else {
 		p.instances <- instance
@@ -81,17 +267,19 @@ func (p *vmPool) RevertInstance(instance *wrappedInstance) {
 }
 
 // shouldDiscard discard instance when
-// error count times more than defaultDiscardCount
+// 1. error count times more than DiscardCount, OR
+// 2. it was interrupted, its memory may be left in any state
 func (p *vmPool) shouldDiscard(instance *wrappedInstance) bool {
-	return instance.errCount > defaultDiscardCount
+	return instance.interrupted || instance.errCount > *p.options.DiscardCount
 }
 
 // CloseInstance close a wasmer instance directly, for cross contract call
 func (p *vmPool) CloseInstance(instance *wrappedInstance) {
 	if instance != nil {
-		if err := CallDeallocate(instance.wasmInstance); err != nil {
-			p.log.Errorf("CallDeallocate(...) error: %v", err)
+		if err := callDeallocate(instance.wasmInstance, instance.exports); err != nil {
+			p.log.Errorf("callDeallocate(...) error: %v", err)
 		}
+		instance.exports.close()
 		instance.wasmInstance.Close()
 		instance = nil
 	}
@@ -104,17 +292,26 @@ func (p *vmPool) NewInstance() (*wrappedInstance, error) {
 
 func (p *vmPool) newInstanceFromModule() (*wrappedInstance, error) {
 
-	imports := wasmergo.NewImportObject()
+	env := &hostEnvironment{log: p.log}
+	imports := newImportObject(p.store, env)
 
 	wasmInstance, err := wasmergo.NewInstance(p.module, imports)
 	if err != nil {
 		p.log.Errorf("newInstanceFromModule fail: %s", err.Error())
 		return nil, err
 	}
+	exports, err := resolveExports(p.module, wasmInstance, p.options.ABI)
+	if err != nil {
+		p.log.Errorf("newInstanceFromModule fail: %s", err.Error())
+		wasmInstance.Close()
+		return nil, err
+	}
+	env.bind(wasmInstance, exports.memory)
 
 	instance := &wrappedInstance{
 		id:           uuid.GetUUID(),
 		wasmInstance: wasmInstance,
+		exports:      exports,
 		lastUseTime:  utils.CurrentTimeMillisSeconds(),
 		createTime:   utils.CurrentTimeMillisSeconds(),
 		errCount:     0,
@@ -122,15 +319,32 @@ func (p *vmPool) newInstanceFromModule() (*wrappedInstance, error) {
 	return instance, nil
 }
 
-func newVmPool(contractId *common.Contract, byteCode []byte, log *logger.CMLogger) (*vmPool, error) {
-	store := wasmergo.NewStore(wasmergo.NewUniversalEngine())
-	if err := wasmergo.ValidateModule(store, byteCode); err != nil {
-		return nil, fmt.Errorf("[%s_%s], byte code validation failed, err = %v", contractId.Name, contractId.Version, err)
+func newVmPool(contractId *common.Contract, byteCode []byte, options *PoolOptions,
+	log *logger.CMLogger) (*vmPool, error) {
+	options = options.withDefaults()
+	if err := options.Validate(); err != nil {
+		return nil, fmt.Errorf("[%s_%s], invalid pool options, err = %v", contractId.Name, contractId.Version, err)
 	}
 
-	module, err := wasmergo.NewModule(store, byteCode, log)
+	// every node must get bit-identical results, see NewDeterministicEngine
+	store := wasmergo.NewStore(wasmergo.NewDeterministicEngine())
+	module, err := compileModule(store, contractId, byteCode, options.ModuleCache, log)
 	if err != nil {
-		return nil, fmt.Errorf("[%s_%s], byte code compile failed", contractId.Name, contractId.Version)
+		return nil, err
+	}
+
+	if err = CheckContractABI(module, options.ABI).Err(); err != nil {
+		return nil, fmt.Errorf("[%s_%s], %s", contractId.Name, contractId.Version, err.Error())
+	}
+
+	if options.LintOptions != nil {
+		report, err := LintContract(module, byteCode, options.LintOptions)
+		if err != nil {
+			return nil, fmt.Errorf("[%s_%s], lint failed, %s", contractId.Name, contractId.Version, err.Error())
+		}
+		if err = report.Err(); err != nil {
+			return nil, fmt.Errorf("[%s_%s], %s", contractId.Name, contractId.Version, err.Error())
+		}
 	}
 
 	vmPool := &vmPool{
@@ -138,7 +352,8 @@ func newVmPool(contractId *common.Contract, byteCode []byte, log *logger.CMLogge
 		byteCode:        byteCode,
 		store:           store,
 		module:          module,
-		instances:       make(chan *wrappedInstance, 5),
+		options:         options,
+		instances:       make(chan *wrappedInstance, options.MaxSize),
 		currentSize:     0,
 		useCount:        0,
 		totalDelay:      0,
@@ -156,6 +371,7 @@ func newVmPool(contractId *common.Contract, byteCode []byte, log *logger.CMLogge
 		return nil, fmt.Errorf("[%s_%s], byte code compile failed, %s", contractId.Name, contractId.Version, err.Error())
 	}
 
+	instance.exports.close()
 	instance.wasmInstance.Close()
 	log.Infof("vm pool verify byteCode finish.")
 
@@ -164,71 +380,86 @@ func newVmPool(contractId *common.Contract, byteCode []byte, log *logger.CMLogge
 	return vmPool, nil
 }
 
+// compileModule load the module from cache, compile and cache it on miss
+func compileModule(store *wasmergo.Store, contractId *common.Contract, byteCode []byte, cache *ModuleCache,
+	log *logger.CMLogger) (*wasmergo.Module, error) {
+	if cache != nil {
+		if module, ok := cache.Load(store, byteCode); ok {
+			log.Infof("[%s_%s], module loaded from cache", contractId.Name, contractId.Version)
+			return module, nil
+		}
+	}
+
+	if err := wasmergo.ValidateModule(store, byteCode); err != nil {
+		return nil, fmt.Errorf("[%s_%s], byte code validation failed, err = %v", contractId.Name, contractId.Version, err)
+	}
+
+	module, err := wasmergo.NewModule(store, byteCode, log)
+	if err != nil {
+		return nil, fmt.Errorf("[%s_%s], byte code compile failed", contractId.Name, contractId.Version)
+	}
+
+	if cache != nil {
+		// a failed store only costs a compile next time
+		if err = cache.Store(store, module, byteCode); err != nil {
+			log.Warnf("[%s_%s], module cache store failed, %s", contractId.Name, contractId.Version, err.Error())
+		}
+	}
+	return module, nil
+}
+
 // startRefreshingLoop refreshing loop manages the vm pool
 // all grow and shrink operations are called here
 func (p *vmPool) startRefreshingLoop() {
 
-	refreshTimer := time.NewTimer(defaultRefreshTime)
-	key := p.contractId.Name + "_" + p.contractId.Version
+	refreshTimer := time.NewTimer(p.options.RefreshTime)
+	key := contractKey(p.contractId)
 	for {
 		select {
 		case <-p.applySignalC:
 			log.Debug("vmPool handling an `apply` Signal")
 			p.applyGrowCount++
 			if p.shouldGrow() {
-				log.Debugf("vmPool should grow %v wrappedInstance.", defaultChangeSize)
-				p.grow(defaultChangeSize)
+				log.Debugf("vmPool should grow %v wrappedInstance.", p.options.ChangeSize)
+				p.grow(p.options.ChangeSize)
 				p.applyGrowCount = 0
 				p.log.Infof("[%s] vm pool grows by %d, the current size is %d",
-					key, defaultChangeSize, p.currentSize)
+					key, p.options.ChangeSize, p.currentSize)
 			}
 		case <-refreshTimer.C:
 			p.log.Debugf("[%s] vmPool handling an `refresh` Signal", key)
 			if p.shouldGrow() {
-				p.grow(defaultChangeSize)
+				p.grow(p.options.ChangeSize)
 				p.applyGrowCount = 0
 				p.log.Infof("[%s] vm pool grows by %d, the current size is %d",
-					key, defaultChangeSize, p.currentSize)
+					key, p.options.ChangeSize, p.currentSize)
 			} else if p.shouldShrink() {
-				p.shrink(defaultChangeSize)
+				p.shrink(p.options.ChangeSize)
 				p.log.Infof("[%s] vm pool shrinks by %d, the current size is %d",
-					key, defaultChangeSize, p.currentSize)
+					key, p.options.ChangeSize, p.currentSize)
 			}
 
 			// other go routine may modify useCount & totalDelay
 			// so we use atomic operation here
 			atomic.StoreInt32(&p.useCount, 0)
 			atomic.StoreInt32(&p.totalDelay, 0)
-			refreshTimer.Reset(defaultRefreshTime)
+			refreshTimer.Reset(p.options.RefreshTime)
 		case <-p.closeC:
 			p.log.Debugf("[%s] vmPool handling an `close` Signal", key)
 			refreshTimer.Stop()
-			for p.currentSize > 0 {
-				instance := <-p.instances
-				if err := CallDeallocate(instance.wasmInstance); err != nil {
-					p.log.Errorf("CallDeallocate(...) error: %v", err)
-				}
-				instance.wasmInstance.Close()
-				p.currentSize--
-			}
+			p.drain()
 			close(p.instances)
+			deleteContractMetrics(key)
 			return
 		case <-p.resetC:
 			p.log.Debugf("[%s] vmPool handling an `reset` Signal", key)
-			for p.currentSize > 0 {
-				instance := <-p.instances
-				if err := CallDeallocate(instance.wasmInstance); err != nil {
-					p.log.Errorf("CallDeallocate(...) error: %v", err)
-				}
-				instance.wasmInstance.Close()
-				p.currentSize--
-			}
-			close(p.instances)
-			p.instances = make(chan *wrappedInstance, defaultMaxSize)
-			p.grow(defaultMinSize)
+			// drained in place, the channel is shared with GetInstance and RevertInstance
+			p.drain()
+			p.grow(*p.options.MinSize)
 		case <-p.removeInstanceC:
 			p.log.Debugf("[%s] vmPool handling an `remove instance` Signal", key)
-			p.currentSize--
+			atomic.AddInt32(&p.currentSize, -1)
+			p.reportSize()
 		case <-p.addInstanceC:
 			p.log.Debugf("[%s] vmPool handling an `add instance` Signal", key)
 			p.grow(1)
@@ -241,38 +472,55 @@ func (p *vmPool) startRefreshingLoop() {
 // 2.1. apply count >= apply threshold, OR
 // 2.2. average delay > delay tolerance (int operation here is safe)
 func (p *vmPool) shouldGrow() bool {
-	if p.currentSize < defaultMinSize {
+	if p.currentSize < *p.options.MinSize {
 		return true
 	}
 
-	if p.currentSize+defaultChangeSize <= defaultMaxSize {
-		if p.applyGrowCount > defaultApplyThreshold {
+	// an empty pool, MinSize 0, grows as soon as an instance is asked for
+	if p.currentSize == 0 && p.applyGrowCount > 0 {
+		return true
+	}
+
+	if p.currentSize+p.options.ChangeSize <= p.options.MaxSize {
+		if p.applyGrowCount > *p.options.ApplyThreshold {
 			return true
 		}
 
-		if p.getAverageDelay() > int32(defaultDelayTolerance) {
+		if p.getAverageDelay() > *p.options.DelayTolerance {
 			return true
 		}
 
-		if p.currentSize < int32(defaultMinSize) {
+		if p.currentSize < *p.options.MinSize {
 			return true
 		}
 	}
 	return false
 }
 
+// grow the pool by count, but never beyond MaxSize
+// or it blocks on the full instance channel
 func (p *vmPool) grow(count int32) {
+	if room := p.options.MaxSize - atomic.LoadInt32(&p.currentSize); count > room {
+		count = room
+	}
 	for count > 0 {
-		size := int32(defaultChangeSize)
+		size := p.options.ChangeSize
 		if count < size {
 			size = count
 		}
 		count -= size
 
 		for i := int32(0); i < size; i++ {
-			instance, _ := p.newInstanceFromModule()
+			instance, err := p.newInstanceFromModule()
+			if err != nil {
+				// a nil instance in the channel would crash the next caller
+				p.log.Errorf("vm pool grow stopped, %s", err.Error())
+				return
+			}
 			p.instances <- instance
 			atomic.AddInt32(&p.currentSize, 1)
+			poolGrowCounter.Inc(contractKey(p.contractId))
+			p.reportSize()
 		}
 		p.log.Infof("vm pool grow size = %d", size)
 	}
@@ -282,8 +530,8 @@ func (p *vmPool) grow(count int32) {
 // 1. current size > min size, AND
 // 2. average delay <= delay tolerance (int operation here is safe)
 func (p *vmPool) shouldShrink() bool {
-	if p.currentSize > defaultMinSize && p.getAverageDelay() <=
-		int32(defaultDelayTolerance) && p.currentSize > defaultChangeSize {
+	if p.currentSize > *p.options.MinSize && p.getAverageDelay() <=
+		*p.options.DelayTolerance && p.currentSize > p.options.ChangeSize {
 		return true
 	}
 	return false
@@ -291,16 +539,32 @@ func (p *vmPool) shouldShrink() bool {
 
 func (p *vmPool) shrink(count int32) {
 	for i := int32(0); i < count; i++ {
-		instance := <-p.instances
-		if err := CallDeallocate(instance.wasmInstance); err != nil {
-			p.log.Errorf("CallDeallocate(...) error: %v", err)
+		p.CloseInstance(<-p.instances)
+		atomic.AddInt32(&p.currentSize, -1)
+		poolShrinkCounter.Inc(contractKey(p.contractId))
+		p.reportSize()
+	}
+}
+
+// drain close every instance of the pool, wait for the ones in use to be reverted
+// and count the discarded ones, so that currentSize drops to 0
+func (p *vmPool) drain() {
+	for atomic.LoadInt32(&p.currentSize) > 0 {
+		select {
+		case instance := <-p.instances:
+			p.CloseInstance(instance)
+		case <-p.removeInstanceC:
 		}
-		instance.wasmInstance.Close()
-		instance = nil
-		p.currentSize--
+		atomic.AddInt32(&p.currentSize, -1)
+		p.reportSize()
 	}
 }
 
+// reportSize export the current size of the pool
+func (p *vmPool) reportSize() {
+	poolSizeGauge.Set(float64(atomic.LoadInt32(&p.currentSize)), contractKey(p.contractId))
+}
+
 // getAverageDelay average delay calculation here maybe not so accurate due to concurrency
 // but we can still use it to decide grow/shrink or not
 func (p *vmPool) getAverageDelay() int32 {
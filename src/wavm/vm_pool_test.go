@@ -0,0 +1,133 @@
+package wavm
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPoolOptionsWithDefaults(t *testing.T) {
+	var nilOptions *PoolOptions
+	assert.Equal(t, DefaultPoolOptions(), nilOptions.withDefaults())
+
+	options := (&PoolOptions{MaxSize: 20, RefreshTime: time.Minute}).withDefaults()
+	assert.Equal(t, int32(20), options.MaxSize)
+	assert.Equal(t, time.Minute, options.RefreshTime)
+	assert.Equal(t, int32(defaultMinSize), *options.MinSize)
+	assert.Equal(t, int32(defaultDiscardCount), *options.DiscardCount)
+	assert.NoError(t, options.Validate())
+
+	// zero is kept when set explicitly
+	options = (&PoolOptions{MinSize: Int32(0), DiscardCount: Int32(0)}).withDefaults()
+	assert.Equal(t, int32(0), *options.MinSize)
+	assert.Equal(t, int32(0), *options.DiscardCount)
+	assert.NoError(t, options.Validate())
+}
+
+func TestPoolOptionsValidate(t *testing.T) {
+	assert.NoError(t, DefaultPoolOptions().Validate())
+
+	options := DefaultPoolOptions()
+	options.MaxSize = *options.MinSize - 1
+	assert.Error(t, options.Validate())
+
+	options = DefaultPoolOptions()
+	options.ChangeSize = options.MaxSize + 1
+	assert.Error(t, options.Validate())
+
+	options = DefaultPoolOptions()
+	options.DiscardCount = Int32(-1)
+	assert.Error(t, options.Validate())
+
+	options = DefaultPoolOptions()
+	options.GetInstanceTimeout = -time.Second
+	assert.Error(t, options.Validate())
+
+	options = DefaultPoolOptions()
+	options.ExecuteTimeout = -time.Second
+	assert.Error(t, options.Validate())
+}
+
+func TestNewVmPoolWithOptions(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	_, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(10), MaxSize: 2}, logger)
+	assert.Error(t, err)
+
+	pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 2, ChangeSize: 1}, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+	assert.Equal(t, 2, cap(pool.instances))
+}
+
+func TestGetInstanceTimeoutAndClose(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1}, logger)
+	assert.NoError(t, err)
+
+	instance, err := pool.GetInstance(context.Background())
+	assert.NoError(t, err)
+
+	// the only instance is in use, so waiting for another one must time out
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+	_, err = pool.GetInstance(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var getErr *GetInstanceError
+	assert.True(t, errors.As(err, &getErr))
+
+	pool.RevertInstance(instance)
+	pool.close()
+
+	_, err = pool.GetInstance(context.Background())
+	assert.True(t, errors.Is(err, ErrPoolClosed))
+
+	runtimeInst := RuntimeInstance{
+		pool: pool,
+		log:  logger,
+	}
+	ret := runtimeInst.Invoke(&contractId, "increase", wasmBytes, map[string][]byte{}, nil, 0)
+	assert.Equal(t, ContractCodePoolUnavailable, ret.Code)
+	assert.Contains(t, ret.Message, ErrPoolClosed.Error())
+}
+
+func TestVmPoolReset(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(1), MaxSize: 1, ChangeSize: 1}, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+	instances := pool.instances
+
+	// the instance in use is drained once reverted, then the pool grows again
+	instance, err := pool.GetInstance(context.Background())
+	assert.NoError(t, err)
+	pool.reset()
+	pool.RevertInstance(instance)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	instance, err = pool.GetInstance(ctx)
+	assert.NoError(t, err)
+	pool.RevertInstance(instance)
+	assert.True(t, instances == pool.instances)
+}
+
+func TestVmPoolMinSizeZero(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	pool, err := newVmPool(&contractId, wasmBytes, &PoolOptions{MinSize: Int32(0), MaxSize: 1, ChangeSize: 1}, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+
+	// the empty pool grows on the first call
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	instance, err := pool.GetInstance(ctx)
+	assert.NoError(t, err)
+	pool.RevertInstance(instance)
+}
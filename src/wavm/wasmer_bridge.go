@@ -0,0 +1,374 @@
+package wavm
+
+import (
+	"fmt"
+
+	"chainmaker.org/chainmaker/common/v2/serialize"
+	"chainmaker.org/chainmaker/logger/v2"
+	"chainmaker.org/chainmaker/protocol/v2"
+	wasmergo "github.com/jhyehuang/wasm-example/pkg/wasmer-go"
+)
+
+const (
+	// importNamespace the module name contracts import host functions from
+	importNamespace = "env"
+	// sysCallImport sys_call(reqHeaderPtr, reqHeaderLen, reqBodyPtr, reqBodyLen) -> signal
+	sysCallImport = "sys_call"
+	// logMessageImport log_message(msgPtr, msgLen)
+	logMessageImport = "log_message"
+
+	// request header keys
+	headerCtxPtr = "ctx_ptr"
+	headerMethod = "method"
+
+	// request body keys
+	bodyKey      = "key"
+	bodyField    = "field"
+	bodyValue    = "value"
+	bodyValuePtr = "value_ptr"
+	bodyTopic    = "topic"
+
+	// contractMethodGetCallArgsLen return the length of the serialized call args
+	contractMethodGetCallArgsLen = "GetCallArgsLen"
+	// contractMethodGetCallArgs copy the serialized call args into contract memory
+	contractMethodGetCallArgs = "GetCallArgs"
+)
+
+// sysCallHandler handle one sys_call method with the request body sent by contract
+type sysCallHandler func(sc *SimContext, memory *wasmergo.Memory, req *serialize.EasyCodec) error
+
+var sysCallHandlers = map[string]sysCallHandler{
+	protocol.ContractMethodGetStateLen:   (*SimContext).getStateLen,
+	protocol.ContractMethodGetState:      (*SimContext).getState,
+	protocol.ContractMethodPutState:      (*SimContext).putState,
+	protocol.ContractMethodDeleteState:   (*SimContext).deleteState,
+	protocol.ContractMethodEmitEvent:     (*SimContext).emitEvent,
+	protocol.ContractMethodSuccessResult: (*SimContext).successResult,
+	protocol.ContractMethodErrorResult:   (*SimContext).errorResult,
+	contractMethodGetCallArgsLen:         (*SimContext).getCallArgsLen,
+	contractMethodGetCallArgs:            (*SimContext).getCallArgs,
+}
+
+// hostEnvironment is shared by the host functions of one wasmer instance,
+// the instance and its memory are bound right after it is created
+type hostEnvironment struct {
+	instance       *wasmergo.Instance
+	exportedMemory *wasmergo.Memory
+	log            *logger.CMLogger
+}
+
+// bind the instance calling the host functions and the memory they read and write
+func (env *hostEnvironment) bind(instance *wasmergo.Instance, memory *wasmergo.Memory) {
+	env.instance = instance
+	env.exportedMemory = memory
+}
+
+// interrupted return an error once the instance is interrupted, the host
+// function then traps so the contract stops even if the interrupt set by
+// the watchdog was lost
+func (env *hostEnvironment) interrupted(function string) error {
+	if env.instance != nil && env.instance.Interrupted() {
+		return fmt.Errorf("%s aborted, instance interrupted", function)
+	}
+	return nil
+}
+
+// memory return the exported memory of the bound instance
+func (env *hostEnvironment) memory() (*wasmergo.Memory, error) {
+	if env.exportedMemory == nil {
+		return nil, fmt.Errorf("host environment is not bound to an instance")
+	}
+	return env.exportedMemory, nil
+}
+
+// newImportObject build the host function namespace imported by contracts
+func newImportObject(store *wasmergo.Store, env *hostEnvironment) *wasmergo.ImportObject {
+	sysCallFunc := wasmergo.NewFunctionWithEnvironment(
+		store,
+		wasmergo.NewFunctionType(
+			wasmergo.NewValueTypes(wasmergo.I32, wasmergo.I32, wasmergo.I32, wasmergo.I32),
+			wasmergo.NewValueTypes(wasmergo.I32),
+		),
+		env,
+		sysCall,
+	)
+
+	logMessageFunc := wasmergo.NewFunctionWithEnvironment(
+		store,
+		wasmergo.NewFunctionType(
+			wasmergo.NewValueTypes(wasmergo.I32, wasmergo.I32),
+			wasmergo.NewValueTypes(),
+		),
+		env,
+		logMessage,
+	)
+
+	imports := wasmergo.NewImportObject()
+	imports.Register(importNamespace, map[string]wasmergo.IntoExtern{
+		sysCallImport:    sysCallFunc,
+		logMessageImport: logMessageFunc,
+	})
+	return imports
+}
+
+// sysCall is the single entry of all chain operations called by contract,
+// the request header carries the context pointer and the method name
+func sysCall(environment interface{}, args []wasmergo.Value) ([]wasmergo.Value, error) {
+	env := environment.(*hostEnvironment)
+
+	memory, err := env.memory()
+	if err != nil {
+		return nil, err
+	}
+
+	headerBytes, err := memory.ReadSlice(int64(args[0].I32()), int64(args[1].I32()))
+	if err != nil {
+		return nil, fmt.Errorf("sys_call read request header failed, %s", err.Error())
+	}
+	header := serialize.NewEasyCodecWithBytes(headerBytes)
+
+	ctxPtr, err := header.GetInt32(headerCtxPtr)
+	if err != nil {
+		return nil, fmt.Errorf("sys_call get [%s] from request header failed, %s", headerCtxPtr, err.Error())
+	}
+	method, err := header.GetString(headerMethod)
+	if err != nil {
+		return nil, fmt.Errorf("sys_call get [%s] from request header failed, %s", headerMethod, err.Error())
+	}
+
+	sc := GetVmBridgeManager().get(ctxPtr)
+	if sc == nil {
+		return nil, fmt.Errorf("sys_call [%s] can't find context of ctx_ptr %d", method, ctxPtr)
+	}
+	// trap right away rather than run host code past the invoke deadline
+	if err = sysCallAborted(env, sc, method); err != nil {
+		return nil, err
+	}
+
+	handler, ok := sysCallHandlers[method]
+	if !ok {
+		sc.Log.Errorf("sys_call method [%s] is not supported", method)
+		return signalResult(protocol.ContractSdkSignalResultFail), nil
+	}
+
+	bodyBytes, err := memory.ReadSlice(int64(args[2].I32()), int64(args[3].I32()))
+	if err != nil {
+		sc.Log.Errorf("sys_call [%s] read request body failed, %s", method, err.Error())
+		return signalResult(protocol.ContractSdkSignalResultFail), nil
+	}
+
+	err = handler(sc, memory, serialize.NewEasyCodecWithBytes(bodyBytes))
+	// the deadline may have passed while the handler ran
+	if abortErr := sysCallAborted(env, sc, method); abortErr != nil {
+		return nil, abortErr
+	}
+	if err != nil {
+		sc.Log.Errorf("sys_call [%s] failed, %s", method, err.Error())
+		return signalResult(protocol.ContractSdkSignalResultFail), nil
+	}
+	return signalResult(protocol.ContractSdkSignalResultSuccess), nil
+}
+
+// sysCallAborted return an error if the invoke of sc is done or its instance interrupted
+func sysCallAborted(env *hostEnvironment, sc *SimContext, method string) error {
+	if sc.ctx != nil && sc.ctx.Err() != nil {
+		return fmt.Errorf("sys_call [%s] aborted, %s", method, sc.ctx.Err().Error())
+	}
+	return env.interrupted(fmt.Sprintf("sys_call [%s]", method))
+}
+
+// logMessage print the message sent by contract
+func logMessage(environment interface{}, args []wasmergo.Value) ([]wasmergo.Value, error) {
+	env := environment.(*hostEnvironment)
+
+	memory, err := env.memory()
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err := memory.ReadSlice(int64(args[0].I32()), int64(args[1].I32()))
+	if err != nil {
+		return nil, fmt.Errorf("log_message read message failed, %s", err.Error())
+	}
+	env.log.Debugf("wasm log>> %s", msg)
+	if err = env.interrupted("log_message"); err != nil {
+		return nil, err
+	}
+	return []wasmergo.Value{}, nil
+}
+
+func signalResult(signal int32) []wasmergo.Value {
+	return []wasmergo.Value{wasmergo.NewI32(signal)}
+}
+
+// getStateLen read the state into GetStateCache and write its length to value_ptr
+func (sc *SimContext) getStateLen(memory *wasmergo.Memory, req *serialize.EasyCodec) error {
+	valuePtr, err := req.GetInt32(bodyValuePtr)
+	if err != nil {
+		return err
+	}
+
+	value, err := sc.getStateFromRequest(req)
+	if err != nil {
+		return err
+	}
+	sc.GetStateCache = value
+
+	return memory.WriteUint32LE(int64(valuePtr), uint32(len(value)))
+}
+
+// getState copy the state cached by getStateLen to value_ptr
+func (sc *SimContext) getState(memory *wasmergo.Memory, req *serialize.EasyCodec) error {
+	valuePtr, err := req.GetInt32(bodyValuePtr)
+	if err != nil {
+		return err
+	}
+
+	if sc.GetStateCache == nil {
+		value, err := sc.getStateFromRequest(req)
+		if err != nil {
+			return err
+		}
+		sc.GetStateCache = value
+	}
+
+	_, err = memory.WriteAt(sc.GetStateCache, int64(valuePtr))
+	sc.GetStateCache = nil
+	return err
+}
+
+func (sc *SimContext) getStateFromRequest(req *serialize.EasyCodec) ([]byte, error) {
+	if err := sc.checkTxSimContext(); err != nil {
+		return nil, err
+	}
+
+	key, err := req.GetString(bodyKey)
+	if err != nil {
+		return nil, err
+	}
+	field, _ := req.GetString(bodyField)
+
+	return sc.TxSimContext.Get(sc.Contract.Name, getKey(key, field))
+}
+
+// putState write the state into TxSimContext
+func (sc *SimContext) putState(_ *wasmergo.Memory, req *serialize.EasyCodec) error {
+	if err := sc.checkTxSimContext(); err != nil {
+		return err
+	}
+
+	key, err := req.GetString(bodyKey)
+	if err != nil {
+		return err
+	}
+	field, _ := req.GetString(bodyField)
+	value, err := req.GetBytes(bodyValue)
+	if err != nil {
+		return err
+	}
+
+	return sc.TxSimContext.Put(sc.Contract.Name, getKey(key, field), value)
+}
+
+// deleteState delete the state from TxSimContext
+func (sc *SimContext) deleteState(_ *wasmergo.Memory, req *serialize.EasyCodec) error {
+	if err := sc.checkTxSimContext(); err != nil {
+		return err
+	}
+
+	key, err := req.GetString(bodyKey)
+	if err != nil {
+		return err
+	}
+	field, _ := req.GetString(bodyField)
+
+	return sc.TxSimContext.Del(sc.Contract.Name, getKey(key, field))
+}
+
+// emitEvent record an event, every item except topic is one piece of event data
+func (sc *SimContext) emitEvent(_ *wasmergo.Memory, req *serialize.EasyCodec) error {
+	topic, err := req.GetString(bodyTopic)
+	if err != nil {
+		return err
+	}
+
+	event := &ContractEvent{
+		Topic: topic,
+	}
+	for _, item := range req.GetItems() {
+		if item.Key == bodyTopic {
+			continue
+		}
+		data, ok := item.Value.(string)
+		if !ok {
+			return fmt.Errorf("event data [%s] is not string type", item.Key)
+		}
+		event.EventData = append(event.EventData, data)
+	}
+	sc.ContractEvents = append(sc.ContractEvents, event)
+	return nil
+}
+
+// successResult save the return payload of contract into ContractResult.Result
+func (sc *SimContext) successResult(_ *wasmergo.Memory, req *serialize.EasyCodec) error {
+	value, err := req.GetBytes(bodyValue)
+	if err != nil {
+		return err
+	}
+
+	if sc.ContractResult.Code == ContractCodeContractError {
+		return fmt.Errorf("contract already reported error [%s]", sc.ContractResult.Message)
+	}
+	sc.ContractResult.Code = ContractCodeSuccess
+	sc.ContractResult.Result = value
+	return nil
+}
+
+// errorResult save the business error reported by contract into ContractResult.Message
+func (sc *SimContext) errorResult(_ *wasmergo.Memory, req *serialize.EasyCodec) error {
+	value, err := req.GetBytes(bodyValue)
+	if err != nil {
+		return err
+	}
+
+	sc.ContractResult.Code = ContractCodeContractError
+	sc.ContractResult.Result = nil
+	sc.ContractResult.Message = string(value)
+	return nil
+}
+
+// getCallArgsLen write the length of the serialized call args to value_ptr
+func (sc *SimContext) getCallArgsLen(memory *wasmergo.Memory, req *serialize.EasyCodec) error {
+	valuePtr, err := req.GetInt32(bodyValuePtr)
+	if err != nil {
+		return err
+	}
+	args := serialize.NewEasyCodecWithMap(sc.parameters).Marshal()
+	return memory.WriteUint32LE(int64(valuePtr), uint32(len(args)))
+}
+
+// getCallArgs copy the serialized call args to value_ptr
+func (sc *SimContext) getCallArgs(memory *wasmergo.Memory, req *serialize.EasyCodec) error {
+	valuePtr, err := req.GetInt32(bodyValuePtr)
+	if err != nil {
+		return err
+	}
+	args := serialize.NewEasyCodecWithMap(sc.parameters).Marshal()
+	_, err = memory.WriteAt(args, int64(valuePtr))
+	return err
+}
+
+func (sc *SimContext) checkTxSimContext() error {
+	if sc.TxSimContext == nil {
+		return fmt.Errorf("TxSimContext of [%s] is not set", sc.method)
+	}
+	return nil
+}
+
+// getKey combine key and field into the key stored in TxSimContext
+func getKey(key string, field string) []byte {
+	if field == "" {
+		return []byte(key)
+	}
+	return []byte(key + protocol.ContractStoreSeparator + field)
+}
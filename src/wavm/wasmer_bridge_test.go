@@ -0,0 +1,77 @@
+package wavm
+
+import (
+	"context"
+	"testing"
+
+	"chainmaker.org/chainmaker/common/v2/serialize"
+	"github.com/jhyehuang/wasm-example/src/wavm/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewVmPoolWithSysCallImports(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	pool, err := newVmPool(&contractId, wasmBytes, nil, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+
+	instance, err := pool.NewInstance()
+	assert.NoError(t, err)
+	defer pool.CloseInstance(instance)
+
+	_, err = instance.wasmInstance.Exports.GetRawFunction("increase")
+	assert.NoError(t, err)
+}
+
+func TestSysCallAborted(t *testing.T) {
+	wasmBytes, contractId, logger := prepareContract("./testdata/rust-counter-2.0.0.wasm", t)
+
+	pool, err := newVmPool(&contractId, wasmBytes, nil, logger)
+	assert.NoError(t, err)
+	defer pool.close()
+
+	instance, err := pool.NewInstance()
+	assert.NoError(t, err)
+	defer pool.CloseInstance(instance)
+
+	env := &hostEnvironment{instance: instance.wasmInstance}
+	ctx, cancel := context.WithCancel(context.Background())
+	sc := &SimContext{ctx: ctx}
+	assert.NoError(t, sysCallAborted(env, sc, "PutState"))
+
+	// a lost interrupt still stops the contract at its next sys_call
+	assert.NoError(t, instance.wasmInstance.Interrupt())
+	assert.Error(t, sysCallAborted(env, sc, "PutState"))
+
+	cancel()
+	assert.Error(t, sysCallAborted(&hostEnvironment{}, sc, "PutState"))
+}
+
+func TestGetKey(t *testing.T) {
+	assert.Equal(t, []byte("count"), getKey("count", ""))
+	assert.Equal(t, []byte("count#test_key"), getKey("count", "test_key"))
+}
+
+func TestSuccessAndErrorResult(t *testing.T) {
+	sc := &SimContext{ContractResult: &common.ContractResult{}}
+
+	req := serialize.NewEasyCodec()
+	req.AddBytes(bodyValue, []byte("100"))
+	assert.NoError(t, sc.successResult(nil, req))
+	assert.Equal(t, ContractCodeSuccess, sc.ContractResult.Code)
+	assert.Equal(t, []byte("100"), sc.ContractResult.Result)
+
+	req = serialize.NewEasyCodec()
+	req.AddBytes(bodyValue, []byte("key not found"))
+	assert.NoError(t, sc.errorResult(nil, req))
+	assert.Equal(t, ContractCodeContractError, sc.ContractResult.Code)
+	assert.Equal(t, "key not found", sc.ContractResult.Message)
+	assert.Nil(t, sc.ContractResult.Result)
+
+	// a success reported after an error must not hide the error
+	req = serialize.NewEasyCodec()
+	req.AddBytes(bodyValue, []byte("100"))
+	assert.Error(t, sc.successResult(nil, req))
+	assert.Equal(t, ContractCodeContractError, sc.ContractResult.Code)
+}